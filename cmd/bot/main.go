@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"novinhub-webhook/internal/app"
+	"novinhub-webhook/internal/bot"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Standalone bot binary for running pattern management separately from the webhook server.
+// Set telegram.embedded to false so the server doesn't poll the same bot.
+func main() {
+	a := app.Setup(app.Bot)
+	if a == nil {
+		return
+	}
+	cfg, logger := a.Config, a.Logger
+
+	// The webhook server runs the bot, and its daily report, while telegram.embedded is true
+	if cfg.Telegram.Embedded {
+		log.Fatal("telegram.embedded is true, so the webhook server runs this bot - set it to false to run cmd/bot")
+	}
+	if cfg.Telegram.Webhook.Enabled() {
+		log.Fatal("Telegram webhook mode needs the HTTP server - use the embedded bot or clear telegram.webhook")
+	}
+
+	// SIGINT (Ctrl+C) and SIGTERM (supervisor, Docker) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize SMS service for test sends and credit; the queue worker belongs to the webhook server
+	smsService := services.NewSMSService(logger, cfg, a.Stats, a.OptOuts)
+
+	// Initialize bot
+	api, err := tgbotapi.NewBotAPI(cfg.Telegram.Token)
+	if err != nil {
+		logger.Fatal("Failed to initialize Telegram bot", "error", err)
+	}
+
+	logger.Info("Telegram bot authorized", "username", api.Self.UserName)
+
+	// The webhook server sends the follow-ups; the standalone bot only shows and stops them
+	scheduler, err := drip.NewScheduler(logger, cfg, smsService)
+	if err != nil {
+		log.Fatal("Failed to initialize drip scheduler:", err)
+	}
+
+	b := bot.New(api, cfg, logger, a.Stats, smsService, a.AuditLog, scheduler)
+	b.SetStandalone(true)
+
+	// Schedule the report on the previous day; the server doesn't, since telegram.embedded is false
+	b.StartDailyReport()
+
+	// Long poll until a shutdown signal arrives
+	go func() {
+		<-ctx.Done()
+		b.Stop()
+	}()
+	b.Run(b.Updates(api, nil))
+
+	logger.Info("👋 Shutdown complete")
+}
@@ -1,249 +1,166 @@
 package main
 
 import (
+	"context"
 	"log"
-	"strconv"
-	"time"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"novinhub-webhook/internal/app"
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/bot"
 	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/handlers"
 	"novinhub-webhook/internal/server"
+	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/stats"
 	"novinhub-webhook/pkg/logger"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-const (
-	BotToken = "8205935967:AAEI2jb_0y0-TlYZ_5gA2wF4cyIr7eaHYuU"
-)
+func main() {
+	a := app.Setup(app.Server)
+	if a == nil {
+		return
+	}
+	cfg, logger := a.Config, a.Logger
+	store, auditLog, optOuts := a.Stats, a.AuditLog, a.OptOuts
 
-// Admin IDs - لیست ادمین‌های مجاز
-var AdminIDs = map[int64]string{
-	76599340:  "Admin Original",      // ادمین اصلی
-	110435852: "MahYaR (@Saeidpour)", // ادمین جدید
-}
+	// SIGINT (Ctrl+C) and SIGTERM (supervisor, Docker) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-// isAdmin بررسی می‌کند که آیا کاربر ادمین است یا نه
-func isAdmin(userID int64) (bool, string) {
-	if name, exists := AdminIDs[userID]; exists {
-		return true, name
-	}
-	return false, ""
-}
+	// Initialize SMS service
+	smsService := services.NewSMSService(logger, cfg, store, optOuts)
+	smsService.RestoreQueue()
 
-func main() {
-	// Load configuration
-	cfg, err := config.Load()
+	// Initialize the scheduler sending each lead's follow-up SMS
+	scheduler, err := drip.NewScheduler(logger, cfg, smsService)
 	if err != nil {
-		log.Fatal("Failed to load configuration:", err)
+		log.Fatal("Failed to initialize drip scheduler:", err)
 	}
 
-	// Initialize logger
-	logger := logger.New()
-
-	// Create server
-	srv := server.New(cfg, logger)
-
-	// Start webhook server in a goroutine
+	// Start the worker sending leads queued while paused or in quiet hours; they join a sequence once sent
+	smsService.OnQueuedSent(scheduler.EnrollQueued)
+	queueDone := make(chan struct{})
 	go func() {
-		if err := srv.Start(); err != nil {
-			log.Fatal("Server failed to start:", err)
-		}
+		smsService.RunQueue(ctx)
+		close(queueDone)
 	}()
 
-	// Start Telegram bot
-	startTelegramBot(cfg, logger)
+	// Start sending follow-ups as they fall due
+	dripDone := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(dripDone)
+	}()
 
-	// Wait forever
-	select {}
-}
+	// Create server
+	srv := server.New(cfg, logger, smsService, store, scheduler)
 
-func startTelegramBot(cfg *config.Config, logger *logger.Logger) {
-	// Initialize bot
-	bot, err := tgbotapi.NewBotAPI(BotToken)
-	if err != nil {
-		logger.Error("Failed to initialize Telegram bot", "error", err)
-		return
+	// The admin API is only served when a token is configured
+	if cfg.AdminAPI.Token != "" {
+		srv.HandleAdmin(handlers.OptOutPath, handlers.NewOptOutHandler(logger, cfg, optOuts, auditLog))
+		srv.HandleAdmin(handlers.SequencesPath, handlers.NewSequenceHandler(logger, cfg, scheduler, auditLog))
+		logger.Info("🔑 Admin API enabled", "paths", handlers.OptOutPath+", "+handlers.SequencesPath)
 	}
 
-	bot.Debug = false
-	logger.Info("Telegram bot authorized", "username", bot.Self.UserName)
-
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-
-	updates := bot.GetUpdatesChan(u)
+	// Start Telegram bot (registers its webhook route before the server starts)
+	var b *bot.Bot
+	var botDone <-chan struct{}
+	if cfg.Telegram.Embedded {
+		b, botDone = startTelegramBot(cfg, logger, srv, store, smsService, auditLog, scheduler)
+	} else {
+		logger.Info("Embedded Telegram bot disabled - run cmd/bot separately")
+	}
 
-	// Handle updates in a goroutine
+	// Start webhook server in a goroutine
+	serverErr := make(chan error, 1)
 	go func() {
-		for update := range updates {
-			if update.Message != nil {
-				handleMessage(bot, update.Message, cfg, logger)
-			} else if update.CallbackQuery != nil {
-				handleCallbackQuery(bot, update.CallbackQuery, cfg, logger)
-			}
-		}
+		serverErr <- srv.Start()
 	}()
-}
 
-func handleMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.Config, logger *logger.Logger) {
-	// Check if message is from admin
-	isAdminUser, adminName := isAdmin(message.From.ID)
-	if !isAdminUser {
-		logger.Info("🚫 Unauthorized access attempt",
-			"user_id", message.From.ID,
-			"username", message.From.UserName,
-			"first_name", message.From.FirstName,
-			"available_admins", len(AdminIDs))
-		return
+	select {
+	case <-ctx.Done():
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatal("Server failed to start:", err)
+		}
 	}
+	stop()
 
-	// Log admin access
-	logger.Info("✅ Admin access granted",
-		"user_id", message.From.ID,
-		"username", message.From.UserName,
-		"first_name", message.From.FirstName,
-		"admin_name", adminName,
-		"message", message.Text)
-
-	switch message.Text {
-	case "/start":
-		sendMainMenu(bot, message.Chat.ID)
-	case "📱 پترن امروز":
-		showCurrentPattern(bot, message.Chat.ID, cfg)
-	case "➡️ برو به پترن بعدی":
-		nextPattern(bot, message.Chat.ID, cfg)
-	case "📋 لیست پترن‌ها":
-		showPatternsList(bot, message.Chat.ID, cfg)
-	case "👥 لیست ادمین‌ها":
-		showAdminsList(bot, message.Chat.ID)
-	default:
-		sendMainMenu(bot, message.Chat.ID)
-	}
-}
+	logger.Info("🛑 Shutting down", "deadline", cfg.ShutdownTimeout().String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout())
+	defer cancel()
 
-func handleCallbackQuery(bot *tgbotapi.BotAPI, callbackQuery *tgbotapi.CallbackQuery, cfg *config.Config, logger *logger.Logger) {
-	// Check if callback is from admin
-	isAdminUser, adminName := isAdmin(callbackQuery.From.ID)
-	if !isAdminUser {
-		logger.Info("🚫 Unauthorized callback attempt",
-			"user_id", callbackQuery.From.ID,
-			"username", callbackQuery.From.UserName,
-			"first_name", callbackQuery.From.FirstName,
-			"available_admins", len(AdminIDs))
-		// Answer callback query with error
-		bot.Request(tgbotapi.NewCallback(callbackQuery.ID, "❌ دسترسی غیرمجاز"))
-		return
+	// Stop accepting requests and let in-flight webhooks finish
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Webhook server did not shut down cleanly", "error", err)
 	}
 
-	// Log admin callback access
-	logger.Info("✅ Admin callback access granted",
-		"user_id", callbackQuery.From.ID,
-		"username", callbackQuery.From.UserName,
-		"first_name", callbackQuery.From.FirstName,
-		"admin_name", adminName,
-		"callback_data", callbackQuery.Data)
-
-	switch callbackQuery.Data {
-	case "current_pattern":
-		showCurrentPattern(bot, callbackQuery.Message.Chat.ID, cfg)
-	case "next_pattern":
-		nextPattern(bot, callbackQuery.Message.Chat.ID, cfg)
-	case "list_patterns":
-		showPatternsList(bot, callbackQuery.Message.Chat.ID, cfg)
-	case "list_admins":
-		showAdminsList(bot, callbackQuery.Message.Chat.ID)
+	// Stop the bot after the server so webhook-mode updates already accepted are handled
+	if b != nil {
+		b.Stop()
+		if !waitFor(shutdownCtx, botDone) {
+			logger.Warn("⚠️ Telegram bot still handling an update at the shutdown deadline")
+		}
 	}
 
-	// Answer callback query
-	bot.Request(tgbotapi.NewCallback(callbackQuery.ID, ""))
-}
-
-func sendMainMenu(bot *tgbotapi.BotAPI, chatID int64) {
-	text := "🤖 ربات مدیریت پترن‌های SMS\n"
-	text += "👋 سلام ! خوش آمدید\n"
-	text += "🔒 دسترسی امنیتی فعال\n\n"
-	text += "لطفاً یکی از گزینه‌های زیر را انتخاب کنید:"
-
-	keyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📱 پترن امروز", "current_pattern"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("➡️ برو به پترن بعدی", "next_pattern"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("📋 لیست پترن‌ها", "list_patterns"),
-		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("👥 لیست ادمین‌ها", "list_admins"),
-		),
-	)
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ReplyMarkup = keyboard
-	bot.Send(msg)
-}
-
-func showCurrentPattern(bot *tgbotapi.BotAPI, chatID int64, cfg *config.Config) {
-	pattern, index, groupName := cfg.GetCurrentPatternInfo()
+	// Let a follow-up being sent finish; unsent steps stay saved for the next start
+	if !waitFor(shutdownCtx, dripDone) {
+		logger.Warn("⚠️ Drip scheduler still sending at the shutdown deadline")
+	}
 
-	text := "📱 پترن فعلی:\n\n"
-	text += "🔹 گروه: " + groupName + "\n"
-	text += "🔹 شماره: " + strconv.Itoa(index) + " از 4\n"
-	text += "🔹 کد پترن: `" + pattern + "`\n\n"
-	text += "⏰ آخرین تغییر: " + time.Now().Format("2006-01-02 15:04:05")
+	// Send or save the leads still queued
+	if !waitFor(shutdownCtx, queueDone) {
+		logger.Warn("⚠️ SMS queue worker still sending at the shutdown deadline")
+	}
+	if err := smsService.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to save SMS queue", "error", err)
+	}
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	bot.Send(msg)
+	logger.Info("👋 Shutdown complete")
 }
 
-func nextPattern(bot *tgbotapi.BotAPI, chatID int64, cfg *config.Config) {
-	pattern, index, groupName := cfg.NextPattern()
-
-	text := "✅ پترن تغییر کرد!\n\n"
-	text += "🔹 گروه جدید: " + groupName + "\n"
-	text += "🔹 شماره: " + strconv.Itoa(index) + " از 4\n"
-	text += "🔹 کد پترن: `" + pattern + "`\n\n"
-	text += "⏰ زمان تغییر: " + time.Now().Format("2006-01-02 15:04:05")
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	bot.Send(msg)
+// waitFor waits for done to close, giving up when ctx ends
+func waitFor(ctx context.Context, done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
-func showPatternsList(bot *tgbotapi.BotAPI, chatID int64, cfg *config.Config) {
-	patterns := cfg.GetPatternsList()
-
-	text := "📋 لیست تمام پترن‌ها:\n\n"
-
-	for _, p := range patterns {
-		status := "❌"
-		if p["is_current"].(bool) {
-			status = "✅ فعلی"
-		}
-
-		text += status + " " + p["name"].(string) + " (" + strconv.Itoa(p["index"].(int)) + "): `" + p["pattern"].(string) + "`\n"
+// startTelegramBot starts the embedded bot; the returned channel closes when it stops handling updates
+func startTelegramBot(cfg *config.Config, logger *logger.Logger, srv *server.Server, store *stats.Store, smsService *services.SMSService, auditLog *audit.Log, scheduler *drip.Scheduler) (*bot.Bot, <-chan struct{}) {
+	// Initialize bot
+	api, err := tgbotapi.NewBotAPI(cfg.Telegram.Token)
+	if err != nil {
+		logger.Error("Failed to initialize Telegram bot", "error", err)
+		return nil, nil
 	}
 
-	text += "\n⏰ آخرین تغییر: " + time.Now().Format("2006-01-02 15:04:05")
+	api.Debug = false
+	logger.Info("Telegram bot authorized", "username", api.Self.UserName)
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	bot.Send(msg)
-}
+	b := bot.New(api, cfg, logger, store, smsService, auditLog, scheduler)
 
-func showAdminsList(bot *tgbotapi.BotAPI, chatID int64) {
-	text := "👥 لیست ادمین‌های سیستم:\n\n"
+	// Receive updates on the HTTP server when configured, otherwise long poll
+	updates := b.Updates(api, srv)
 
-	for userID, name := range AdminIDs {
-		text += "🔹 " + name + "\n"
-		text += "   ID: `" + strconv.FormatInt(userID, 10) + "`\n\n"
-	}
+	// Schedule the report on the previous day
+	b.StartDailyReport()
 
-	text += "📊 تعداد کل ادمین‌ها: " + strconv.Itoa(len(AdminIDs))
+	// Handle updates in a goroutine
+	done := make(chan struct{})
+	go func() {
+		b.Run(updates)
+		close(done)
+	}()
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "Markdown"
-	bot.Send(msg)
+	return b, done
 }
@@ -156,7 +156,7 @@ server {
         # Timeouts
         proxy_connect_timeout 5s;
         proxy_send_timeout 10s;
-        proxy_read_timeout 10s;
+        proxy_read_timeout 45s;  # Matches server.write_timeout
         
         # Only allow POST requests
         limit_except POST {
@@ -210,6 +210,9 @@ group=$APP_USER
 autostart=true
 autorestart=true
 redirect_stderr=true
+; Give the service longer than server.shutdown_timeout to drain before SIGKILL
+stopsignal=TERM
+stopwaitsecs=20
 stdout_logfile=/var/log/$APP_NAME/supervisor.log
 stdout_logfile_maxbytes=10MB
 stdout_logfile_backups=5
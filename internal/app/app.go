@@ -0,0 +1,162 @@
+// Package app holds the startup shared by the webhook server (cmd/server) and the standalone bot (cmd/bot)
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/optout"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/pkg/logger"
+)
+
+// Binaries calling Setup
+const (
+	Server = "server" // cmd/server; records config reloads in the audit log
+	Bot    = "bot"    // cmd/bot; leaves those entries to the server watching the same file
+)
+
+// App is the configuration, logger and file-backed stores both binaries run on
+type App struct {
+	Config   *config.Config
+	Logger   *logger.Logger
+	Stats    *stats.Store
+	AuditLog *audit.Log
+	OptOuts  *optout.List
+}
+
+// Setup parses the command line, loads and validates the configuration, starts the logger and
+// the config watcher and opens the stores. It returns nil when --print-config or --check-config
+// asked only for output; startup failures are fatal. binary is Server or Bot.
+func Setup(binary string) *App {
+	loadOptions := config.RegisterFlags(flag.CommandLine)
+	checkConfig := flag.Bool("check-config", false, "validate the configuration, print the problems found and exit")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration and where each value came from, then exit")
+	flag.Parse()
+
+	// Load configuration: base file, config.<mode>.yaml overlay, environment, then flags
+	cfg, err := config.LoadWithOptions(*loadOptions)
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	if *printConfig {
+		cfg.PrintEffective(os.Stdout)
+		return nil
+	}
+
+	validation := cfg.Validate()
+	if *checkConfig {
+		fmt.Print(validation)
+		if !validation.OK() {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	// Initialize logger
+	logger := logger.New()
+	logger.Redact(cfg.Secrets()...)
+
+	for _, warning := range validation.Warnings {
+		logger.Warn("⚠️ Configuration warning", "problem", warning)
+	}
+	for _, problem := range validation.Errors {
+		logger.Error("❌ Configuration error", "problem", problem)
+	}
+	if !validation.OK() && cfg.IsProduction() {
+		log.Fatal("Refusing to start in production with an invalid configuration (run with --check-config for details)")
+	}
+
+	if err := logger.SetLevelName(cfg.LogLevel()); err != nil {
+		logger.Warn("Invalid log level - using info", "level", cfg.LogLevel(), "error", err)
+	}
+
+	// Initialize stats store
+	store, err := stats.NewStore(logger, cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize stats store:", err)
+	}
+
+	// Initialize audit log of administrative actions
+	auditLog, err := audit.NewLog(logger, cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize audit log:", err)
+	}
+
+	// Initialize the opt-out (do-not-contact) list checked before every SMS
+	optOuts, err := optout.NewList(logger, cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize opt-out list:", err)
+	}
+
+	// Apply pattern, SMS, log level, rate limit and admin changes without a restart
+	if binary == Server {
+		watchConfig(cfg, logger, auditLog)
+	} else {
+		watchConfig(cfg, logger, nil)
+	}
+
+	return &App{
+		Config:   cfg,
+		Logger:   logger,
+		Stats:    store,
+		AuditLog: auditLog,
+		OptOuts:  optOuts,
+	}
+}
+
+// reloadActions maps the settings in config.ReloadResult.Changes to audit actions
+var reloadActions = map[string]string{
+	"telegram.admins":      audit.ActionAdminsChange,
+	"sms.patterns.current": audit.ActionPatternChange,
+	"sms.enabled":          audit.ActionSMSEnabled,
+}
+
+// watchConfig logs what each config file change applied, rejected or left for a restart,
+// and records admin, pattern and sms.enabled changes in auditLog unless it is nil
+func watchConfig(cfg *config.Config, logger *logger.Logger, auditLog *audit.Log) {
+	watching := cfg.Watch(func(result config.ReloadResult) {
+		if result.Rejected() {
+			for _, problem := range result.Validation.Errors {
+				logger.Error("❌ Configuration reload rejected", "problem", problem)
+			}
+			return
+		}
+
+		if err := logger.SetLevelName(cfg.LogLevel()); err != nil {
+			logger.Warn("Invalid log level - keeping previous level", "level", cfg.LogLevel(), "error", err)
+		}
+
+		for _, warning := range result.Validation.Warnings {
+			logger.Warn("⚠️ Configuration warning", "problem", warning)
+		}
+		if len(result.Applied) > 0 {
+			logger.Info("🔄 Configuration reloaded", "applied", strings.Join(result.Applied, ", "))
+		}
+		if len(result.RestartRequired) > 0 {
+			logger.Warn("⚠️ Configuration changes need a restart to take effect", "keys", strings.Join(result.RestartRequired, ", "))
+		}
+
+		if auditLog != nil {
+			for _, change := range result.Changes {
+				auditLog.Record(audit.Entry{
+					Actor:  config.ReloadActor,
+					Action: reloadActions[change.Key],
+					Before: change.Before,
+					After:  change.After,
+					Source: audit.SourceConfig,
+				})
+			}
+		}
+	})
+
+	if !watching {
+		logger.Info("No config file loaded - configuration hot reload disabled")
+	}
+}
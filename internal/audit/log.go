@@ -0,0 +1,237 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/pkg/logger"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionPatternChange = "pattern_change"
+	ActionSMSPause      = "sms_pause"
+	ActionSMSResume     = "sms_resume"
+	ActionTestSMS       = "test_sms"
+	ActionOptOutAdd     = "optout_add"
+	ActionOptOutRemove  = "optout_remove"
+	ActionOptOutImport  = "optout_import"
+	ActionLeadConverted = "lead_converted"
+	ActionAdminsChange  = "admins_change"
+	ActionSMSEnabled    = "sms_enabled"
+)
+
+// Sources an action can come from
+const (
+	SourceBot    = "bot"
+	SourceAPI    = "api"
+	SourceConfig = "config" // A config file edit applied by hot reload
+)
+
+// Entry is a single administrative action
+type Entry struct {
+	Time    time.Time `json:"time"`
+	ActorID int64     `json:"actor_id,omitempty"`
+	Actor   string    `json:"actor"`
+	Action  string    `json:"action"`
+	Before  string    `json:"before,omitempty"`
+	After   string    `json:"after,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+	Source  string    `json:"source"`
+}
+
+// Log is an append-only JSON lines audit log; entries are never rewritten or pruned
+// The server and the standalone bot append to the same file, so reads pick up the other's entries
+type Log struct {
+	logger   *logger.Logger
+	filePath string
+
+	mu      sync.RWMutex
+	entries []Entry
+	modTime time.Time // Of the file when last read or written
+}
+
+// NewLog creates the audit log and loads previously recorded entries
+func NewLog(logger *logger.Logger, cfg *config.Config) (*Log, error) {
+	l := &Log{
+		logger:   logger,
+		filePath: cfg.Audit.FilePath,
+	}
+
+	if l.filePath == "" {
+		logger.Warn("⚠️ Audit file path not configured - audit log will be kept in memory only")
+		return l, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("📜 Audit log initialized",
+		"file_path", l.filePath,
+		"entries", len(l.entries))
+
+	return l, nil
+}
+
+// load replaces the entries in memory with those in the audit file
+func (l *Log) load() error {
+	file, err := os.Open(l.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			l.logger.Warn("Skipping malformed audit line", "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit file: %w", err)
+	}
+
+	l.entries = entries
+	l.modTime = info.ModTime()
+	return nil
+}
+
+// refresh rereads the file if another process (e.g. the server's admin API, for the standalone bot) appended to it since
+func (l *Log) refresh() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshLocked()
+}
+
+// refreshLocked is refresh for callers holding mu
+func (l *Log) refreshLocked() {
+	if l.filePath == "" {
+		return
+	}
+
+	info, err := os.Stat(l.filePath)
+	if err != nil || !info.ModTime().After(l.modTime) {
+		return
+	}
+
+	if err := l.load(); err != nil {
+		l.logger.Error("Failed to reload audit file - showing entries in memory", "error", err)
+	}
+}
+
+// Record appends an entry to the audit log
+func (l *Log) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Pick up the other process's entries first, or our own write would hide them behind a newer mod time
+	l.refreshLocked()
+	l.entries = append(l.entries, entry)
+
+	l.logger.Info("📜 Audit",
+		"actor", entry.Actor,
+		"action", entry.Action,
+		"before", entry.Before,
+		"after", entry.After,
+		"source", entry.Source)
+
+	if l.filePath == "" {
+		return
+	}
+
+	if err := l.appendToFile(entry); err != nil {
+		l.logger.Error("Failed to persist audit entry", "error", err, "action", entry.Action)
+	}
+}
+
+// appendToFile writes a single entry as a JSON line; callers hold mu
+func (l *Log) appendToFile(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(l.filePath); err == nil {
+		l.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// Recent returns the last n entries, newest first
+func (l *Log) Recent(n int) []Entry {
+	l.refresh()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if n > len(l.entries) {
+		n = len(l.entries)
+	}
+
+	recent := make([]Entry, 0, n)
+	for i := len(l.entries) - 1; i >= len(l.entries)-n; i-- {
+		recent = append(recent, l.entries[i])
+	}
+	return recent
+}
+
+// Export returns every entry as JSON lines, oldest first
+func (l *Log) Export() ([]byte, error) {
+	l.refresh()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range l.entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
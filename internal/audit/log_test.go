@@ -0,0 +1,47 @@
+package audit_test
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/pkg/logger"
+)
+
+func TestLogShowsEntriesAppendedByAnotherProcess(t *testing.T) {
+	log := logger.New()
+	log.SetOutput(io.Discard)
+
+	cfg := &config.Config{}
+	cfg.Audit.FilePath = filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// The standalone bot and the server each open the same file
+	bot, err := audit.NewLog(log, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := audit.NewLog(log, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot.Record(audit.Entry{Actor: "owner", Action: audit.ActionPatternChange, Source: audit.SourceBot})
+	time.Sleep(10 * time.Millisecond)
+	server.Record(audit.Entry{Actor: "api", Action: audit.ActionOptOutAdd, Source: audit.SourceAPI})
+	time.Sleep(10 * time.Millisecond)
+	bot.Record(audit.Entry{Actor: "owner", Action: audit.ActionSMSPause, Source: audit.SourceBot})
+
+	recent := bot.Recent(10)
+	if len(recent) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(recent))
+	}
+	if recent[1].Source != audit.SourceAPI {
+		t.Fatalf("expected the server's entry second newest, got %+v", recent[1])
+	}
+	if got := len(server.Recent(10)); got != 3 {
+		t.Fatalf("expected the server to see 3 entries, got %d", got)
+	}
+}
@@ -0,0 +1,21 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Sender is the narrow interface handlers use to talk to Telegram
+// Tests substitute bottest.FakeSender to capture outgoing messages
+type Sender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// Updater receives updates from Telegram, either by long polling or via webhook
+type Updater interface {
+	Sender
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	StopReceivingUpdates()
+	GetWebhookInfo() (tgbotapi.WebhookInfo, error)
+}
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// recentAuditEntries is how many actions the bot shows
+const recentAuditEntries = 20
+
+// recordAudit records an administrative action taken from the bot by the current admin
+func (b *Bot) recordAudit(c *Context, action, before, after, detail string) {
+	b.auditLog.Record(audit.Entry{
+		ActorID: c.Admin.ID,
+		Actor:   c.Admin.Name,
+		Action:  action,
+		Before:  before,
+		After:   after,
+		Detail:  detail,
+		Source:  audit.SourceBot,
+	})
+}
+
+// showAuditLog shows the most recent administrative actions
+func (b *Bot) showAuditLog(c *Context) {
+	entries := b.auditLog.Recent(recentAuditEntries)
+	if len(entries) == 0 {
+		b.sendText(c.ChatID, c.T("audit.empty"))
+		return
+	}
+
+	text := c.T("audit.title", len(entries)) + "\n\n"
+	for _, entry := range entries {
+		text += formatAuditEntry(c.Admin.Language, entry) + "\n\n"
+	}
+
+	// Plain text: actor names and details are free-form
+	msg := tgbotapi.NewMessage(c.ChatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.audit_export"), "audit_export"),
+		),
+	)
+	b.send(msg)
+}
+
+// exportAuditLog sends the whole audit log as a JSON lines document
+func (b *Bot) exportAuditLog(c *Context) {
+	data, err := b.auditLog.Export()
+	if err != nil {
+		b.logger.Error("Failed to export audit log", "error", err)
+		b.sendText(c.ChatID, c.T("audit.export_failed"))
+		return
+	}
+
+	if len(data) == 0 {
+		b.sendText(c.ChatID, c.T("audit.empty"))
+		return
+	}
+
+	name := "audit-" + utils.TehranNow().Format("20060102-1504") + ".jsonl"
+	b.send(tgbotapi.NewDocument(c.ChatID, tgbotapi.FileBytes{Name: name, Bytes: data}))
+}
+
+// formatAuditEntry renders a single audit entry
+func formatAuditEntry(lang Lang, entry audit.Entry) string {
+	text := "🔹 " + formatDateTime(lang, entry.Time) + " — " + entry.Actor + " (" + entry.Source + ")\n"
+	text += "   " + tr(lang, "audit.action."+entry.Action)
+
+	switch {
+	case entry.Before != "" && entry.After != "":
+		text += ": " + entry.Before + " → " + entry.After
+	case entry.After != "":
+		text += ": " + entry.After
+	case entry.Before != "":
+		text += ": " + entry.Before
+	}
+
+	if entry.Detail != "" {
+		text += "\n   " + entry.Detail
+	}
+	return text
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"runtime/debug"
+
+	"novinhub-webhook/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Admin is a Telegram user allowed to use the bot
+type Admin struct {
+	ID       int64
+	Name     string
+	Owner    bool
+	Language Lang
+}
+
+// isAdmin بررسی می‌کند که آیا کاربر ادمین است یا نه
+func (b *Bot) isAdmin(userID int64) (Admin, bool) {
+	for _, admin := range b.config.Admins() {
+		if admin.ID == userID {
+			return b.newAdmin(admin), true
+		}
+	}
+	return Admin{}, false
+}
+
+// newAdmin builds an Admin from its config record; a language chosen in the bot overrides the configured one
+func (b *Bot) newAdmin(admin config.TelegramAdmin) Admin {
+	lang, ok := b.state.language(admin.ID)
+	if !ok {
+		lang = parseLang(admin.Language)
+	}
+	return Admin{ID: admin.ID, Name: admin.Name, Owner: admin.Owner, Language: lang}
+}
+
+// admins returns every current admin
+func (b *Bot) admins() []Admin {
+	records := b.config.Admins()
+	admins := make([]Admin, 0, len(records))
+	for _, admin := range records {
+		admins = append(admins, b.newAdmin(admin))
+	}
+	return admins
+}
+
+// Auth rejects updates from users who are not admins
+func (b *Bot) Auth(next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		admin, ok := b.isAdmin(c.From.ID)
+		if !ok {
+			if c.IsCallback() {
+				b.logger.Info("🚫 Unauthorized callback attempt",
+					"user_id", c.From.ID,
+					"username", c.From.UserName,
+					"first_name", c.From.FirstName,
+					"available_admins", len(b.config.Admins()))
+				// Answer callback query with error
+				// The user's language is unknown, so the default bundle is used
+				b.sender.Request(tgbotapi.NewCallback(c.Update.CallbackQuery.ID, tr(LangFa, "auth.unauthorized")))
+			} else {
+				b.logger.Info("🚫 Unauthorized access attempt",
+					"user_id", c.From.ID,
+					"username", c.From.UserName,
+					"first_name", c.From.FirstName,
+					"available_admins", len(b.config.Admins()))
+			}
+			return
+		}
+
+		c.Admin = admin
+		next(c)
+	}
+}
+
+// LogAccess logs every authorized update
+func (b *Bot) LogAccess(next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if c.IsCallback() {
+			b.logger.Info("✅ Admin callback access granted",
+				"user_id", c.From.ID,
+				"username", c.From.UserName,
+				"first_name", c.From.FirstName,
+				"admin_name", c.Admin.Name,
+				"callback_data", c.Text)
+		} else {
+			b.logger.Info("✅ Admin access granted",
+				"user_id", c.From.ID,
+				"username", c.From.UserName,
+				"first_name", c.From.FirstName,
+				"admin_name", c.Admin.Name,
+				"message", c.Text)
+		}
+
+		next(c)
+	}
+}
+
+// Recover keeps a panicking handler from taking down the update loop
+func (b *Bot) Recover(next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				b.logger.Error("Bot handler panicked", "panic", r, "text", c.Text, "stack", string(debug.Stack()))
+			}
+		}()
+
+		next(c)
+	}
+}
+
+// RequireOwner restricts a handler to owners
+func (b *Bot) RequireOwner(next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if !c.Admin.Owner {
+			b.sendText(c.ChatID, c.T("auth.owner_only"))
+			return
+		}
+
+		next(c)
+	}
+}
+
+// RequireServer restricts a handler to the bot embedded in the webhook server
+func (b *Bot) RequireServer(next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if b.standalone {
+			b.sendText(c.ChatID, c.T("standalone.server_only"))
+			return
+		}
+
+		next(c)
+	}
+}
@@ -0,0 +1,99 @@
+package bot_test
+
+import (
+	"testing"
+
+	"novinhub-webhook/internal/bot/bottest"
+	"novinhub-webhook/internal/config"
+)
+
+const (
+	ownerID    = 1001
+	adminID    = 1002
+	strangerID = 9999
+)
+
+// newHarness returns a harness with an owner and a regular admin, both using English
+func newHarness() *bottest.Harness {
+	return bottest.New(bottest.Config(
+		config.TelegramAdmin{ID: ownerID, Name: "Owner", Owner: true, Language: "en"},
+		config.TelegramAdmin{ID: adminID, Name: "Admin", Language: "en"},
+	))
+}
+
+func TestUnauthorizedMessageGetsNoReply(t *testing.T) {
+	h := newHarness()
+
+	for _, text := range []string{"/start", "/phone 09121234567", "hello"} {
+		h.SendText(strangerID, text)
+	}
+
+	h.AssertNoMessages(t)
+	if answers := h.Sender.CallbackAnswers(); len(answers) != 0 {
+		t.Fatalf("expected no callback answers, got %d", len(answers))
+	}
+}
+
+func TestUnauthorizedCallbackIsRejected(t *testing.T) {
+	h := newHarness()
+
+	h.Press(strangerID, "sms_pause:0")
+
+	h.AssertNoMessages(t)
+	// The stranger's language is unknown, so the Persian bundle answers
+	h.AssertCallbackAnswer(t, "❌ دسترسی غیرمجاز")
+	if h.SMS.IsPaused() {
+		t.Fatal("unauthorized callback paused SMS sending")
+	}
+}
+
+func TestRequireOwnerRejectsAdmins(t *testing.T) {
+	h := newHarness()
+
+	h.Press(adminID, "sms_pause:0")
+	h.AssertLastMessageContains(t, "Only the bot owner")
+	if h.SMS.IsPaused() {
+		t.Fatal("non-owner paused SMS sending")
+	}
+
+	h.SendText(adminID, "/optin 09121234567")
+	h.AssertLastMessageContains(t, "Only the bot owner")
+}
+
+func TestRequireOwnerAllowsOwners(t *testing.T) {
+	h := newHarness()
+
+	h.Press(ownerID, "sms_pause:0")
+
+	if !h.SMS.IsPaused() {
+		t.Fatal("owner could not pause SMS sending")
+	}
+	if got := h.SMS.PauseState().By; got != "Owner" {
+		t.Fatalf("expected pause by Owner, got %q", got)
+	}
+	bottest.AssertKeyboard(t, h.LastMessage(t), "sms_resume")
+}
+
+func TestStartShowsMainMenu(t *testing.T) {
+	h := newHarness()
+
+	h.SendText(adminID, "/start")
+
+	msg := h.LastMessage(t)
+	if msg.ChatID != adminID {
+		t.Fatalf("expected menu in chat %d, got %d", adminID, msg.ChatID)
+	}
+	h.AssertLastMessageContains(t, "SMS Pattern Management Bot")
+	bottest.AssertKeyboard(t, msg,
+		"current_pattern", "next_pattern", "list_patterns", "list_admins", "stats", "lookup_phone",
+		"sms_switch", "test_sms", "optout", "drip", "audit", "language")
+}
+
+func TestAuthorizedCallbackIsAnswered(t *testing.T) {
+	h := newHarness()
+
+	h.Press(adminID, "list_patterns")
+
+	h.AssertCallbackAnswer(t, "")
+	h.AssertLastMessageContains(t, "testpattern1")
+}
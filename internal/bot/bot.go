@@ -0,0 +1,323 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandlerFunc handles a single update routed to it
+type HandlerFunc func(c *Context)
+
+// Middleware wraps a handler with cross-cutting behaviour such as auth or logging
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Context carries a single update through middleware and handlers
+type Context struct {
+	Update tgbotapi.Update
+	ChatID int64
+	From   *tgbotapi.User
+	Text   string // Message text or callback data
+	Args   string // Text following a command or callback prefix
+	Admin  Admin  // Set by the auth middleware
+}
+
+// IsCallback reports whether the update is an inline keyboard callback
+func (c *Context) IsCallback() bool {
+	return c.Update.CallbackQuery != nil
+}
+
+// pendingInput is a prompt awaiting a free-text reply, with the context chosen so far
+type pendingInput struct {
+	action string
+	arg    string
+}
+
+// Bot is the SMS pattern management bot
+type Bot struct {
+	sender     Sender
+	config     *config.Config
+	logger     *logger.Logger
+	stats      *stats.Store
+	smsService *services.SMSService
+	auditLog   *audit.Log
+	drip       *drip.Scheduler
+	state      *stateStore
+	standalone bool // Running apart from the webhook server (cmd/bot)
+
+	texts            map[string]HandlerFunc // Exact message texts (menu buttons, commands)
+	commands         map[string]HandlerFunc // Slash commands that take arguments
+	callbacks        map[string]HandlerFunc // Exact callback data
+	callbackPrefixes map[string]HandlerFunc // Callback data prefixes carrying an argument
+	inputs           map[string]HandlerFunc // Replies to a previous prompt
+	fallback         HandlerFunc
+	middleware       []Middleware
+
+	pendingMu sync.Mutex
+	pending   map[int64]pendingInput
+
+	undoMu     sync.Mutex
+	switchSeq  int64
+	lastSwitch patternSwitch
+
+	stop        chan struct{} // Closed by Stop
+	stopOnce    sync.Once
+	stopUpdates func()         // Stops the long poller, when polling
+	background  sync.WaitGroup // Work handlers left running, e.g. test sends; Run waits for it
+}
+
+// New creates a bot with the default middleware and handlers registered
+func New(sender Sender, cfg *config.Config, logger *logger.Logger, store *stats.Store, smsService *services.SMSService, auditLog *audit.Log, scheduler *drip.Scheduler) *Bot {
+	b := &Bot{
+		sender:           sender,
+		config:           cfg,
+		logger:           logger,
+		stats:            store,
+		smsService:       smsService,
+		auditLog:         auditLog,
+		drip:             scheduler,
+		texts:            make(map[string]HandlerFunc),
+		commands:         make(map[string]HandlerFunc),
+		callbacks:        make(map[string]HandlerFunc),
+		callbackPrefixes: make(map[string]HandlerFunc),
+		inputs:           make(map[string]HandlerFunc),
+		pending:          make(map[int64]pendingInput),
+		stop:             make(chan struct{}),
+	}
+
+	state, err := loadState(cfg.Telegram.StateFile)
+	if err != nil {
+		logger.Warn("⚠️ Failed to load bot state - using configured preferences", "file_path", cfg.Telegram.StateFile, "error", err)
+	}
+	b.state = state
+
+	// The active pattern's last change survives restarts while the pattern stays active
+	if saved, ok := state.patternChange(); ok {
+		cfg.RestorePatternChange(saved.Pattern, config.PatternChange{At: saved.At, By: saved.By})
+	}
+
+	b.Use(b.Recover, b.Auth, b.LogAccess)
+	b.registerHandlers()
+
+	return b
+}
+
+// SetStandalone marks the bot as running in its own process; actions on the webhook server's
+// in-memory state (pattern switching, the kill switch) are then refused
+func (b *Bot) SetStandalone(standalone bool) {
+	b.standalone = standalone
+}
+
+// Use appends middleware; the first registered runs outermost
+func (b *Bot) Use(middleware ...Middleware) {
+	b.middleware = append(b.middleware, middleware...)
+}
+
+// HandleText registers a handler for an exact message text
+func (b *Bot) HandleText(text string, handler HandlerFunc) {
+	b.texts[text] = handler
+}
+
+// HandleCommand registers a handler for "/command args" messages
+func (b *Bot) HandleCommand(command string, handler HandlerFunc) {
+	b.commands[command] = handler
+}
+
+// HandleCallback registers a handler for exact callback data
+func (b *Bot) HandleCallback(data string, handler HandlerFunc) {
+	b.callbacks[data] = handler
+}
+
+// HandleCallbackPrefix registers a handler for callback data of the form prefix + argument
+func (b *Bot) HandleCallbackPrefix(prefix string, handler HandlerFunc) {
+	b.callbackPrefixes[prefix] = handler
+}
+
+// HandleInput registers a handler for free-text replies to a prompt set with awaitInput
+func (b *Bot) HandleInput(action string, handler HandlerFunc) {
+	b.inputs[action] = handler
+}
+
+// HandleDefault registers the handler for messages nothing else matched
+func (b *Bot) HandleDefault(handler HandlerFunc) {
+	b.fallback = handler
+}
+
+// Run processes updates until the channel is closed or Stop is called
+// Updates already received when Stop is called (e.g. webhook calls answered with 200) are still handled,
+// and Run returns only after the background work they started (test sends) has finished
+func (b *Bot) Run(updates tgbotapi.UpdatesChannel) {
+	defer b.background.Wait()
+
+	for {
+		select {
+		case <-b.stop:
+			b.drain(updates)
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			b.HandleUpdate(update)
+		}
+	}
+}
+
+// drain handles the updates waiting in the channel without waiting for more
+func (b *Bot) drain(updates tgbotapi.UpdatesChannel) {
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			b.HandleUpdate(update)
+		default:
+			return
+		}
+	}
+}
+
+// Stop stops receiving updates and the daily report; Run returns once the updates already received are handled
+func (b *Bot) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+		if b.stopUpdates != nil {
+			b.stopUpdates()
+		}
+	})
+}
+
+// HandleUpdate routes a single update through middleware to its handler
+func (b *Bot) HandleUpdate(update tgbotapi.Update) {
+	c := newContext(update)
+	if c == nil {
+		return
+	}
+
+	handler := HandlerFunc(b.route)
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		handler = b.middleware[i](handler)
+	}
+
+	handler(c)
+}
+
+// newContext builds a context for messages and callbacks; other update types are ignored
+func newContext(update tgbotapi.Update) *Context {
+	switch {
+	case update.Message != nil && update.Message.From != nil:
+		return &Context{
+			Update: update,
+			ChatID: update.Message.Chat.ID,
+			From:   update.Message.From,
+			Text:   update.Message.Text,
+		}
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+		return &Context{
+			Update: update,
+			ChatID: update.CallbackQuery.Message.Chat.ID,
+			From:   update.CallbackQuery.From,
+			Text:   update.CallbackQuery.Data,
+		}
+	}
+
+	return nil
+}
+
+// route dispatches an authorized update to the matching handler
+func (b *Bot) route(c *Context) {
+	if c.IsCallback() {
+		b.routeCallback(c)
+		// Answer callback query
+		b.sender.Request(tgbotapi.NewCallback(c.Update.CallbackQuery.ID, ""))
+		return
+	}
+
+	// Free-text replies to a previous prompt
+	if input := b.popPendingInput(c.ChatID); input.action != "" {
+		if handler, ok := b.inputs[input.action]; ok {
+			c.Args = input.arg
+			handler(c)
+			return
+		}
+	}
+
+	if handler, ok := b.texts[c.Text]; ok {
+		handler(c)
+		return
+	}
+
+	if strings.HasPrefix(c.Text, "/") {
+		command, args, _ := strings.Cut(strings.TrimPrefix(c.Text, "/"), " ")
+		if handler, ok := b.commands[command]; ok {
+			c.Args = strings.TrimSpace(args)
+			handler(c)
+			return
+		}
+	}
+
+	if b.fallback != nil {
+		b.fallback(c)
+	}
+}
+
+// routeCallback dispatches inline keyboard callbacks
+func (b *Bot) routeCallback(c *Context) {
+	if handler, ok := b.callbacks[c.Text]; ok {
+		handler(c)
+		return
+	}
+
+	for prefix, handler := range b.callbackPrefixes {
+		if args, ok := strings.CutPrefix(c.Text, prefix); ok {
+			c.Args = args
+			handler(c)
+			return
+		}
+	}
+}
+
+// awaitInput marks a chat as waiting for a free-text reply
+func (b *Bot) awaitInput(chatID int64, action string, arg string) {
+	b.pendingMu.Lock()
+	b.pending[chatID] = pendingInput{action: action, arg: arg}
+	b.pendingMu.Unlock()
+}
+
+// popPendingInput returns and clears the input a chat is waiting for
+func (b *Bot) popPendingInput(chatID int64) pendingInput {
+	b.pendingMu.Lock()
+	defer b.pendingMu.Unlock()
+
+	input := b.pending[chatID]
+	delete(b.pending, chatID)
+	return input
+}
+
+// send delivers a message and logs failures
+func (b *Bot) send(c tgbotapi.Chattable) {
+	if _, err := b.sender.Send(c); err != nil {
+		b.logger.Error("Failed to send Telegram message", "error", err)
+	}
+}
+
+// sendText sends a plain text message
+func (b *Bot) sendText(chatID int64, text string) {
+	b.send(tgbotapi.NewMessage(chatID, text))
+}
+
+// sendMarkdown sends a Markdown formatted message
+func (b *Bot) sendMarkdown(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	b.send(msg)
+}
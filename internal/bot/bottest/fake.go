@@ -0,0 +1,77 @@
+package bottest
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// FakeSender implements bot.Sender and records everything the bot sends
+type FakeSender struct {
+	mu       sync.Mutex
+	sent     []tgbotapi.Chattable
+	requests []tgbotapi.Chattable
+}
+
+// Send records an outgoing message
+func (f *FakeSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, c)
+	return tgbotapi.Message{MessageID: len(f.sent)}, nil
+}
+
+// Request records an outgoing request such as a callback answer
+func (f *FakeSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, c)
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// Messages returns the text messages sent so far
+func (f *FakeSender) Messages() []tgbotapi.MessageConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var messages []tgbotapi.MessageConfig
+	for _, c := range f.sent {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// Sent returns every Chattable passed to Send
+func (f *FakeSender) Sent() []tgbotapi.Chattable {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]tgbotapi.Chattable(nil), f.sent...)
+}
+
+// CallbackAnswers returns the callback query answers sent so far
+func (f *FakeSender) CallbackAnswers() []tgbotapi.CallbackConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var answers []tgbotapi.CallbackConfig
+	for _, c := range f.requests {
+		if answer, ok := c.(tgbotapi.CallbackConfig); ok {
+			answers = append(answers, answer)
+		}
+	}
+	return answers
+}
+
+// Reset forgets everything recorded so far
+func (f *FakeSender) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = nil
+	f.requests = nil
+}
@@ -0,0 +1,234 @@
+// Package bottest provides a fake Telegram sender and a harness that feeds
+// synthetic updates to the bot, so handlers can be exercised without Telegram.
+package bottest
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/bot"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/optout"
+	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/pkg/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Harness wires a bot to a FakeSender with an in-memory stats store
+type Harness struct {
+	Bot    *bot.Bot
+	Sender *FakeSender
+	Config *config.Config
+	Stats  *stats.Store
+	SMS    *services.SMSService
+	Audit  *audit.Log
+	OptOut *optout.List
+	Drip   *drip.Scheduler
+
+	nextID int64
+}
+
+// Config returns a minimal configuration that passes Validate, with the given admins and four patterns
+// SMS sending is disabled and no API key is set, so nothing reaches the provider
+func Config(admins ...config.TelegramAdmin) *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10,
+			WriteTimeout:    45,
+			ShutdownTimeout: 15,
+		},
+		Logging: config.LoggingConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Webhook: config.WebhookConfig{
+			MaxRequestSize:    1 << 20,
+			ProcessingTimeout: 30,
+		},
+		SMS: config.SMSConfig{
+			Provider: "ippanel",
+			Retry: config.RetryConfig{
+				MaxAttempts:  3,
+				DelaySeconds: 1,
+			},
+			Patterns: config.PatternConfig{
+				Enabled: true,
+				List:    []string{"testpattern1", "testpattern2", "testpattern3", "testpattern4"},
+			},
+		},
+		Telegram: config.TelegramConfig{
+			Admins:            admins,
+			UndoWindowMinutes: 10,
+		},
+		Env: config.EnvironmentConfig{
+			Mode: "development",
+		},
+	}
+}
+
+// New creates a harness around cfg; logs are discarded and nothing is written to disk
+func New(cfg *config.Config) *Harness {
+	log := logger.New()
+	log.SetOutput(io.Discard)
+
+	// Empty file paths keep the stores in memory
+	cfg.Stats.FilePath = ""
+	cfg.Audit.FilePath = ""
+	cfg.Telegram.StateFile = ""
+	cfg.SMS.QueueFile = ""
+	cfg.SMS.PauseFile = ""
+	cfg.OptOut.FilePath = ""
+	cfg.Drip.FilePath = ""
+
+	store, err := stats.NewStore(log, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	auditLog, err := audit.NewLog(log, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	optOuts, err := optout.NewList(log, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	smsService := services.NewSMSService(log, cfg, store, optOuts)
+	scheduler, err := drip.NewScheduler(log, cfg, smsService)
+	if err != nil {
+		panic(err)
+	}
+	sender := &FakeSender{}
+
+	return &Harness{
+		Bot:    bot.New(sender, cfg, log, store, smsService, auditLog, scheduler),
+		Sender: sender,
+		Config: cfg,
+		Stats:  store,
+		SMS:    smsService,
+		Audit:  auditLog,
+		OptOut: optOuts,
+		Drip:   scheduler,
+	}
+}
+
+// MessageUpdate builds a private chat message update from userID
+func (h *Harness) MessageUpdate(userID int64, text string) tgbotapi.Update {
+	id := atomic.AddInt64(&h.nextID, 1)
+	return tgbotapi.Update{
+		UpdateID: int(id),
+		Message: &tgbotapi.Message{
+			MessageID: int(id),
+			From:      &tgbotapi.User{ID: userID, FirstName: "user" + strconv.FormatInt(userID, 10)},
+			Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+			Text:      text,
+		},
+	}
+}
+
+// CallbackUpdate builds an inline keyboard callback update from userID
+func (h *Harness) CallbackUpdate(userID int64, data string) tgbotapi.Update {
+	id := atomic.AddInt64(&h.nextID, 1)
+	return tgbotapi.Update{
+		UpdateID: int(id),
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:   "callback-" + strconv.FormatInt(id, 10),
+			From: &tgbotapi.User{ID: userID, FirstName: "user" + strconv.FormatInt(userID, 10)},
+			Message: &tgbotapi.Message{
+				MessageID: int(id),
+				Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+			},
+			Data: data,
+		},
+	}
+}
+
+// SendText delivers a text message from userID to the bot
+func (h *Harness) SendText(userID int64, text string) {
+	h.Bot.HandleUpdate(h.MessageUpdate(userID, text))
+}
+
+// Press delivers a callback for an inline keyboard button pressed by userID
+func (h *Harness) Press(userID int64, data string) {
+	h.Bot.HandleUpdate(h.CallbackUpdate(userID, data))
+}
+
+// AssertNoMessages fails if the bot sent any message
+func (h *Harness) AssertNoMessages(t testing.TB) {
+	t.Helper()
+
+	if messages := h.Sender.Messages(); len(messages) != 0 {
+		t.Fatalf("expected no messages, got %d; first: %q", len(messages), messages[0].Text)
+	}
+}
+
+// LastMessage returns the most recent message, failing if none was sent
+func (h *Harness) LastMessage(t testing.TB) tgbotapi.MessageConfig {
+	t.Helper()
+
+	messages := h.Sender.Messages()
+	if len(messages) == 0 {
+		t.Fatalf("expected a message, got none")
+	}
+	return messages[len(messages)-1]
+}
+
+// AssertLastMessageContains fails unless the most recent message contains substr
+func (h *Harness) AssertLastMessageContains(t testing.TB, substr string) {
+	t.Helper()
+
+	if msg := h.LastMessage(t); !strings.Contains(msg.Text, substr) {
+		t.Fatalf("expected last message to contain %q, got %q", substr, msg.Text)
+	}
+}
+
+// AssertCallbackAnswer fails unless the most recent callback answer has the given text
+func (h *Harness) AssertCallbackAnswer(t testing.TB, text string) {
+	t.Helper()
+
+	answers := h.Sender.CallbackAnswers()
+	if len(answers) == 0 {
+		t.Fatalf("expected a callback answer, got none")
+	}
+	if got := answers[len(answers)-1].Text; got != text {
+		t.Fatalf("expected callback answer %q, got %q", text, got)
+	}
+}
+
+// KeyboardData returns the callback data of every inline button on a message, row by row
+func KeyboardData(msg tgbotapi.MessageConfig) []string {
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok {
+		return nil
+	}
+
+	var data []string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil {
+				data = append(data, *button.CallbackData)
+			}
+		}
+	}
+	return data
+}
+
+// AssertKeyboard fails unless the message's inline keyboard has exactly the given callback data
+func AssertKeyboard(t testing.TB, msg tgbotapi.MessageConfig, want ...string) {
+	t.Helper()
+
+	got := KeyboardData(msg)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected keyboard %v, got %v", want, got)
+	}
+}
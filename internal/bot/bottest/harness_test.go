@@ -0,0 +1,15 @@
+package bottest
+
+import (
+	"testing"
+
+	"novinhub-webhook/internal/config"
+)
+
+func TestConfigValidates(t *testing.T) {
+	cfg := Config(config.TelegramAdmin{ID: 1, Name: "owner", Owner: true})
+
+	if result := cfg.Validate(); !result.OK() {
+		t.Fatalf("harness config should be valid:\n%s", result)
+	}
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// recentEnrollments is how many active follow-up sequences the bot lists
+const recentEnrollments = 15
+
+// showDrip shows the leads whose follow-up SMS are still scheduled
+func (b *Bot) showDrip(c *Context) {
+	active := b.drip.Active()
+	text := c.T("drip.title", len(active)) + "\n\n"
+
+	if !b.config.DripEnabled() {
+		text += c.T("drip.disabled") + "\n\n"
+	}
+	if len(active) == 0 {
+		text += c.T("drip.empty") + "\n"
+	}
+	if len(active) > recentEnrollments {
+		text += c.T("drip.recent", recentEnrollments) + "\n"
+		active = active[:recentEnrollments]
+	}
+	for _, enrollment := range active {
+		text += formatEnrollmentSummary(c.Admin.Language, enrollment) + "\n"
+	}
+
+	text += "\n" + c.T("drip.hint")
+	b.sendText(c.ChatID, text)
+}
+
+// convertedCommand handles "/converted <phone>" and the lookup's converted button, stopping the lead's follow-ups
+func (b *Bot) convertedCommand(c *Context) {
+	phone := utils.NormalizeIranianPhone(c.Args)
+	if phone == "" {
+		b.sendText(c.ChatID, c.T("drip.converted_usage"))
+		return
+	}
+
+	enrollment, err := b.drip.MarkConverted(phone, c.Admin.Name)
+	if err != nil {
+		b.sendText(c.ChatID, c.T("drip.not_enrolled", phone))
+		return
+	}
+
+	b.recordAudit(c, audit.ActionLeadConverted, "", phone, enrollment.Sequence)
+	b.sendText(c.ChatID, c.T("drip.converted", phone, skippedSteps(enrollment)))
+}
+
+// formatEnrollmentSummary renders an active sequence as one line with its next step
+func formatEnrollmentSummary(lang Lang, enrollment drip.Enrollment) string {
+	text := "🔹 " + enrollment.Phone + " — " + enrollment.Sequence
+	if next := enrollment.NextStep(); next >= 0 {
+		text += "\n   " + tr(lang, "drip.next_step", next+1, len(enrollment.Steps), formatDateTime(lang, enrollment.Steps[next].DueAt))
+	}
+	return text
+}
+
+// formatEnrollment renders a lead's sequence step by step as Markdown for the phone lookup
+func formatEnrollment(lang Lang, enrollment drip.Enrollment) string {
+	text := tr(lang, "drip.lookup_title", enrollment.Sequence, tr(lang, "drip.status."+enrollment.Status))
+	if !enrollment.Active() && enrollment.StoppedBy != "" {
+		text += " (" + tgbotapi.EscapeText(tgbotapi.ModeMarkdown, enrollment.StoppedBy) + ")"
+	}
+
+	for i, step := range enrollment.Steps {
+		pattern := step.Pattern
+		if pattern == "" {
+			pattern = tr(lang, "drip.daily_pattern")
+		}
+
+		when := formatDateTime(lang, step.DueAt)
+		if step.Status == drip.StepSent {
+			when = formatDateTime(lang, step.SentAt)
+		}
+		text += "\n" + tr(lang, "drip.step."+step.Status, i+1, pattern, when)
+	}
+	return text
+}
+
+// skippedSteps counts the follow-ups a stopped sequence will no longer send
+func skippedSteps(enrollment drip.Enrollment) int {
+	skipped := 0
+	for _, step := range enrollment.Steps {
+		if step.Status == drip.StepSkipped {
+			skipped++
+		}
+	}
+	return skipped
+}
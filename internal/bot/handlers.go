@@ -0,0 +1,53 @@
+package bot
+
+// registerHandlers wires menu texts, commands and callbacks to their handlers
+func (b *Bot) registerHandlers() {
+	b.HandleText("/start", b.sendMainMenu)
+	b.HandleDefault(b.sendMainMenu)
+
+	b.handleMenuButton("button.current_pattern", "current_pattern", b.showCurrentPattern)
+	b.handleMenuButton("button.next_pattern", "next_pattern", b.RequireServer(b.nextPattern))
+	b.HandleCallbackPrefix("undo_pattern:", b.RequireServer(b.undoPatternSwitch))
+	b.handleMenuButton("button.list_patterns", "list_patterns", b.showPatternsList)
+	b.handleMenuButton("button.list_admins", "list_admins", b.showAdminsList)
+	b.handleMenuButton("button.stats", "stats", b.showStats)
+
+	b.handleMenuButton("button.lookup_phone", "lookup_phone", b.askPhoneLookup)
+	b.HandleCommand("phone", b.phoneCommand)
+	b.HandleInput(inputPhoneLookup, b.showPhoneHistory)
+
+	b.handleMenuButton("button.sms_switch", "sms_switch", b.RequireServer(b.showSMSSwitch))
+	b.HandleCallbackPrefix("sms_pause:", b.RequireServer(b.RequireOwner(b.pauseSMS)))
+	b.HandleCallback("sms_resume", b.RequireServer(b.RequireOwner(b.resumeSMS)))
+
+	b.handleMenuButton("button.test_sms", "test_sms", b.chooseTestPattern)
+	b.HandleCallbackPrefix("test_pattern:", b.askTestPhone)
+	b.HandleInput(inputTestSMS, b.sendTestSMS)
+
+	b.handleMenuButton("button.optout", "optout", b.showOptOuts)
+	b.HandleCommand("optout", b.optOutCommand)
+	b.HandleCommand("optin", b.RequireOwner(b.optInCommand))
+	b.HandleCallback("optout_add", b.askOptOutPhone)
+	b.HandleCallback("optout_export", b.exportOptOuts)
+	b.HandleInput(inputOptOut, b.addOptOut)
+
+	b.handleMenuButton("button.drip", "drip", b.showDrip)
+	b.HandleCommand("converted", b.convertedCommand)
+	b.HandleCallbackPrefix("converted:", b.convertedCommand)
+
+	b.handleMenuButton("button.audit", "audit", b.showAuditLog)
+	b.HandleCommand("audit", b.showAuditLog)
+	b.HandleCallback("audit_export", b.RequireOwner(b.exportAuditLog))
+
+	b.handleMenuButton("button.language", "language", b.chooseLanguage)
+	b.HandleCommand("lang", b.languageCommand)
+	b.HandleCallbackPrefix("lang:", b.setLanguage)
+}
+
+// handleMenuButton registers a menu action for its callback data and for its label typed in any language
+func (b *Bot) handleMenuButton(labelKey string, callback string, handler HandlerFunc) {
+	for _, lang := range languages {
+		b.HandleText(tr(lang, labelKey), handler)
+	}
+	b.HandleCallback(callback, handler)
+}
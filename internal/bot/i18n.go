@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"novinhub-webhook/internal/utils"
+)
+
+// Lang is a bot interface language
+type Lang string
+
+// Supported languages; Persian is the default
+const (
+	LangFa Lang = "fa"
+	LangEn Lang = "en"
+)
+
+// languages lists the supported languages in menu order
+var languages = []Lang{LangFa, LangEn}
+
+// catalog holds the message bundles, keyed by language and message key
+var catalog = map[Lang]map[string]string{
+	LangFa: messagesFa,
+	LangEn: messagesEn,
+}
+
+// parseLang returns the language for a config or callback value, falling back to Persian
+func parseLang(value string) Lang {
+	switch Lang(strings.ToLower(strings.TrimSpace(value))) {
+	case LangEn:
+		return LangEn
+	default:
+		return LangFa
+	}
+}
+
+// tr returns the message for key in lang, formatted with args
+// Missing translations fall back to Persian, then to the key itself
+func tr(lang Lang, key string, args ...interface{}) string {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		if msg, ok = catalog[LangFa][key]; !ok {
+			msg = key
+		}
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// T returns a message in the admin's language
+func (c *Context) T(key string, args ...interface{}) string {
+	return tr(c.Admin.Language, key, args...)
+}
+
+// formatDateTime renders a time in Tehran: Jalali for Persian, Gregorian otherwise
+func formatDateTime(lang Lang, t time.Time) string {
+	if lang == LangFa {
+		return utils.FormatJalaliDateTime(t)
+	}
+	return t.In(utils.TehranLocation()).Format("2006-01-02 15:04:05")
+}
+
+// formatDate renders a date in Tehran: Jalali for Persian, Gregorian otherwise
+func formatDate(lang Lang, t time.Time) string {
+	if lang == LangFa {
+		return utils.FormatJalaliDate(t)
+	}
+	return t.In(utils.TehranLocation()).Format("2006-01-02")
+}
+
+// groupName returns the localized name of a 1-based pattern group
+// Index 0 means no pattern is configured
+func groupName(lang Lang, index int) string {
+	key := fmt.Sprintf("pattern.group.%d", index)
+	if _, ok := catalog[LangFa][key]; !ok {
+		return fmt.Sprintf("#%d", index)
+	}
+	return tr(lang, key)
+}
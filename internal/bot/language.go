@@ -0,0 +1,47 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// chooseLanguage offers the supported bot languages
+func (b *Bot) chooseLanguage(c *Context) {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, lang := range languages {
+		label := tr(lang, "language.name."+string(lang))
+		if lang == c.Admin.Language {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "lang:"+string(lang)))
+	}
+
+	msg := tgbotapi.NewMessage(c.ChatID, c.T("language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	b.send(msg)
+}
+
+// languageCommand handles "/lang <fa|en>", offering the choices when no language is given
+func (b *Bot) languageCommand(c *Context) {
+	if c.Args == "" {
+		b.chooseLanguage(c)
+		return
+	}
+
+	b.setLanguage(c)
+}
+
+// setLanguage stores the admin's language and shows the menu in it
+func (b *Bot) setLanguage(c *Context) {
+	lang := parseLang(c.Args)
+	c.Admin.Language = lang
+
+	if err := b.state.setLanguage(c.Admin.ID, lang); err != nil {
+		b.logger.Error("Failed to save bot language", "user_id", c.Admin.ID, "language", lang, "error", err)
+		b.sendText(c.ChatID, c.T("language.save_failed"))
+	}
+
+	b.logger.Info("🌐 Bot language changed", "user_id", c.Admin.ID, "admin_name", c.Admin.Name, "language", lang)
+
+	b.sendText(c.ChatID, c.T("language.changed"))
+	b.sendMainMenu(c)
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// inputPhoneLookup waits for the phone number to look up
+	inputPhoneLookup = "phone_lookup"
+	// maxLookupEvents limits how many history entries fit in one bot message
+	maxLookupEvents = 30
+	// maxDeliveryLookups limits provider status calls per lookup
+	maxDeliveryLookups = 5
+)
+
+// askPhoneLookup prompts the admin to type the phone number to look up
+func (b *Bot) askPhoneLookup(c *Context) {
+	b.awaitInput(c.ChatID, inputPhoneLookup, "")
+
+	b.sendText(c.ChatID, c.T("lookup.ask"))
+}
+
+// phoneCommand handles "/phone <number>", prompting when no number is given
+func (b *Bot) phoneCommand(c *Context) {
+	if c.Args == "" {
+		b.askPhoneLookup(c)
+		return
+	}
+
+	c.Text = c.Args
+	b.showPhoneHistory(c)
+}
+
+// showPhoneHistory shows every recorded lead, SMS attempt and dedup decision for a phone number
+func (b *Bot) showPhoneHistory(c *Context) {
+	phone := utils.NormalizeIranianPhone(c.Text)
+	if phone == "" {
+		b.sendText(c.ChatID, c.T("phone.invalid", c.Text))
+		return
+	}
+
+	events := b.stats.ByPhone(phone)
+
+	text := c.T("lookup.title", phone) + "\n\n"
+	if entry, ok := b.smsService.OptOuts().Get(phone); ok {
+		text += c.T("lookup.opted_out_status", formatDateTime(c.Admin.Language, entry.AddedAt)) + "\n\n"
+	}
+	enrollment, enrolled := b.drip.Progress(phone)
+	if enrolled {
+		text += formatEnrollment(c.Admin.Language, enrollment) + "\n\n"
+	}
+
+	if len(events) == 0 {
+		text += c.T("lookup.empty")
+		b.sendLookup(c, text, enrollment)
+		return
+	}
+
+	if len(events) > maxLookupEvents {
+		text += c.T("lookup.truncated", maxLookupEvents, len(events)) + "\n\n"
+		events = events[len(events)-maxLookupEvents:]
+	}
+
+	// Only query the provider for the most recent sends
+	deliveryStatuses := make(map[int64]string)
+	for i := len(events) - 1; i >= 0 && len(deliveryStatuses) < maxDeliveryLookups; i-- {
+		if events[i].Kind != stats.KindSMSSent || events[i].MessageID == 0 {
+			continue
+		}
+		status, err := b.smsService.GetDeliveryStatus(events[i].MessageID)
+		if err != nil {
+			b.logger.Warn("Failed to fetch delivery status", "message_id", events[i].MessageID, "error", err)
+			status = c.T("status.unknown")
+		}
+		deliveryStatuses[events[i].MessageID] = status
+	}
+
+	for _, event := range events {
+		text += formatPhoneEvent(c.Admin.Language, event, deliveryStatuses[event.MessageID]) + "\n"
+	}
+
+	b.sendLookup(c, text, enrollment)
+}
+
+// sendLookup sends a phone history, with a button to mark the lead converted while its follow-ups are scheduled
+func (b *Bot) sendLookup(c *Context, text string, enrollment drip.Enrollment) {
+	msg := tgbotapi.NewMessage(c.ChatID, text)
+	msg.ParseMode = "Markdown"
+	if enrollment.Active() {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(c.T("button.converted"), "converted:"+enrollment.Phone),
+			),
+		)
+	}
+	b.send(msg)
+}
+
+// formatPhoneEvent renders a single history entry
+func formatPhoneEvent(lang Lang, event stats.Event, deliveryStatus string) string {
+	when := formatDateTime(lang, event.Time)
+
+	switch event.Kind {
+	case stats.KindLead:
+		line := tr(lang, "lookup.lead", when)
+		line += "\n" + tr(lang, "lookup.lead_details", event.Platform, event.UserID, event.LeadID)
+		return line
+	case stats.KindSMSSent:
+		line := tr(lang, "lookup.sent", when)
+		if event.Test {
+			line = tr(lang, "lookup.test_sent", when, event.UserID)
+		}
+		line += "\n" + tr(lang, "lookup.sent_details", event.Pattern, event.MessageID)
+		if deliveryStatus != "" {
+			line += "\n" + tr(lang, "lookup.delivery", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, deliveryStatus))
+		}
+		return line
+	case stats.KindSMSDuplicate:
+		return tr(lang, "lookup.duplicate", when, event.UserID)
+	case stats.KindSMSQueued:
+		return tr(lang, "lookup.queued", when)
+	case stats.KindSMSDeferred:
+		return tr(lang, "lookup.deferred", when)
+	case stats.KindSMSFailed:
+		line := tr(lang, "lookup.failed", when)
+		if event.Test {
+			line = tr(lang, "lookup.test_failed", when, event.UserID)
+		}
+		line += "\n" + tr(lang, "lookup.failed_details", event.Pattern, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Error))
+		return line
+	case stats.KindSMSOptedOut:
+		return tr(lang, "lookup.opted_out", when)
+	case stats.KindSMSLimited:
+		return tr(lang, "lookup.limited", when, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Error))
+	case stats.KindSMSTimeout:
+		line := tr(lang, "lookup.timeout", when)
+		line += "\n" + tr(lang, "lookup.failed_details", event.Pattern, tgbotapi.EscapeText(tgbotapi.ModeMarkdown, event.Error))
+		return line
+	}
+
+	return "🔹 " + when + " — " + event.Kind
+}
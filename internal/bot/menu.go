@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *Bot) sendMainMenu(c *Context) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.current_pattern"), "current_pattern"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.next_pattern"), "next_pattern"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.list_patterns"), "list_patterns"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.list_admins"), "list_admins"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.stats"), "stats"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.lookup_phone"), "lookup_phone"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.sms_switch"), "sms_switch"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.test_sms"), "test_sms"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.optout"), "optout"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.drip"), "drip"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.audit"), "audit"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.language"), "language"),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(c.ChatID, c.T("menu.title"))
+	msg.ReplyMarkup = keyboard
+	b.send(msg)
+}
+
+func (b *Bot) showAdminsList(c *Context) {
+	admins := b.admins()
+
+	text := c.T("admins.title") + "\n\n"
+
+	for _, admin := range admins {
+		text += "🔹 " + admin.Name
+		if admin.Owner {
+			text += " 👑"
+		}
+		text += "\n"
+		text += "   ID: `" + strconv.FormatInt(admin.ID, 10) + "`\n\n"
+	}
+
+	text += c.T("admins.total", len(admins))
+
+	b.sendMarkdown(c.ChatID, text)
+}
@@ -0,0 +1,200 @@
+package bot
+
+// messagesEn is the English message bundle
+var messagesEn = map[string]string{
+	// Main menu
+	"menu.title":             "🤖 SMS Pattern Management Bot\n👋 Hello, welcome!\n🔒 Secure access enabled\n\nPlease choose one of the options below:",
+	"button.current_pattern": "📱 Today's pattern",
+	"button.next_pattern":    "➡️ Switch to next pattern",
+	"button.list_patterns":   "📋 Pattern list",
+	"button.list_admins":     "👥 Admin list",
+	"button.stats":           "📊 Statistics",
+	"button.lookup_phone":    "🔎 Phone history",
+	"button.sms_switch":      "⏯️ Pause/resume SMS",
+	"button.test_sms":        "🧪 Send test SMS",
+	"button.language":        "🌐 زبان / Language",
+	"button.resume":          "▶️ Resume sending",
+
+	// Access control
+	"auth.unauthorized":      "❌ Access denied",
+	"auth.owner_only":        "🔒 Only the bot owner can perform this action",
+	"standalone.server_only": "⚠️ Not available in the standalone bot: this changes the webhook server's live state. Use the bot embedded in the server (telegram.embedded: true), or switch patterns by editing sms.patterns.current in the config file.",
+
+	// Language
+	"language.choose":      "🌐 Choose the bot language:",
+	"language.name.fa":     "فارسی",
+	"language.name.en":     "English",
+	"language.changed":     "✅ Bot language set to English",
+	"language.save_failed": "⚠️ Could not save the chosen language; the previous language will be used after a restart",
+
+	// Admins
+	"admins.title": "👥 System admins:",
+	"admins.total": "📊 Total admins: %d",
+
+	// Patterns
+	"pattern.group.0":       "No pattern configured",
+	"pattern.group.1":       "Group 1",
+	"pattern.group.2":       "Group 2",
+	"pattern.group.3":       "Group 3",
+	"pattern.group.4":       "Group 4",
+	"pattern.current.title": "📱 Current pattern:",
+	"pattern.group":         "🔹 Group: %s",
+	"pattern.new_group":     "🔹 New group: %s",
+	"pattern.number":        "🔹 Number: %d of %d",
+	"pattern.code":          "🔹 Pattern code: `%s`",
+	"pattern.last_changed":  "⏰ Last changed: %s",
+	"pattern.changed_by":    "👤 Changed by: %s",
+	"pattern.changed.title": "✅ Pattern changed!",
+	"pattern.changed_at":    "⏰ Changed at: %s",
+	"patterns.title":        "📋 All patterns:",
+	"patterns.current":      "✅ current",
+
+	// Stats
+	"stats.title":         "📊 System statistics:",
+	"stats.today":         "📅 Today",
+	"stats.last7":         "🗓️ Last 7 days",
+	"stats.last30":        "🗓️ Last 30 days",
+	"stats.webhooks":      "📨 Webhook events: %d",
+	"stats.leads":         "📥 Leads: %d",
+	"stats.sms_sent":      "✅ SMS sent: %d",
+	"stats.sms_duplicate": "⏭️ Blocked as duplicate: %d",
+	"stats.sms_failed":    "❌ Failed sends: %d",
+	"stats.sms_timeout":   "⏱️ Timed-out sends: %d",
+	"stats.sms_limited":   "🚧 Blocked by phone limits: %d",
+	"stats.sms_opted_out": "🚫 Blocked as opted out: %d",
+	"stats.updated":       "⏰ Updated: %s",
+
+	// Daily report
+	"report.title":           "📊 Daily report - %s",
+	"report.leads":           "📥 Leads received: %d",
+	"report.valid_phones":    "📱 Valid phone numbers: %d",
+	"report.invalid_phones":  "⚠️ Invalid phone numbers: %d",
+	"report.patterns_used":   "🧩 Patterns used:",
+	"report.current_pattern": "📌 Current pattern: %s `%s`",
+	"report.credit":          "💰 Remaining credit: %s IRR",
+	"report.credit_unknown":  "💰 Remaining credit: unknown",
+
+	// Phone lookup
+	"phone.invalid":           "❌ Invalid phone number: %s",
+	"status.unknown":          "unknown",
+	"lookup.ask":              "🔎 Send the mobile number to look up:\n(e.g. 09121234567 or +989121234567)",
+	"lookup.title":            "🔎 History of `%s`",
+	"lookup.empty":            "Nothing has been recorded for this number.",
+	"lookup.truncated":        "⚠️ Showing only the last %d of %d entries",
+	"lookup.lead":             "📥 %s — lead received",
+	"lookup.lead_details":     "   Platform: `%s` | User: `%s` | Lead: `%s`",
+	"lookup.sent":             "✅ %s — SMS sent",
+	"lookup.test_sent":        "🧪 %s — test SMS sent (by %s)",
+	"lookup.sent_details":     "   Pattern: `%s` | Message ID: `%d`",
+	"lookup.delivery":         "   Delivery status: %s",
+	"lookup.duplicate":        "⏭️ %s — send blocked (already sent today)\n   User: `%s`",
+	"lookup.queued":           "📥 %s — queued because sending was paused",
+	"lookup.deferred":         "🌙 %s — queued until quiet hours end",
+	"lookup.failed":           "❌ %s — send failed",
+	"lookup.timeout":          "⏱️ %s — send timed out",
+	"lookup.limited":          "🚧 %s — not sent, phone limit reached\n   %s",
+	"lookup.opted_out":        "🚫 %s — not sent, number opted out",
+	"lookup.opted_out_status": "🚫 Opted out since %s - no SMS will be sent",
+	"lookup.test_failed":      "🧪 %s — test SMS failed (by %s)",
+	"lookup.failed_details":   "   Pattern: `%s` | Error: %s",
+
+	// SMS kill switch
+	"switch.title":          "⏯️ SMS sending status:",
+	"switch.disabled":       "📵 SMS sending is disabled in the configuration (`sms.enabled: false`)",
+	"switch.paused":         "⏸️ Paused",
+	"switch.by":             "👤 By: %s",
+	"switch.since":          "⏰ Since: %s",
+	"switch.no_auto_resume": "🔁 Auto-resume: none",
+	"switch.auto_resume":    "🔁 Auto-resume: %s",
+	"switch.running":        "▶️ Sending",
+	"switch.quiet":          "🌙 Quiet hours: lead SMS are queued until %s",
+	"switch.queued":         "📥 Queued SMS: %d",
+	"pause.manual":          "⏸️ Until further notice",
+	"pause.1h":              "⏸️ 1 hour",
+	"pause.3h":              "⏸️ 3 hours",
+	"pause.12h":             "⏸️ 12 hours",
+
+	// Test SMS
+	"test.no_patterns":     "❌ No patterns are configured",
+	"test.choose":          "🧪 Send test SMS\n\nChoose a pattern:",
+	"test.invalid_pattern": "❌ The selected pattern is not valid",
+	"test.ask_phone":       "🧪 Pattern `%s` selected.\n\n📱 Enter the mobile number that should receive the test SMS:",
+	"test.sending":         "⏳ Sending the test SMS, the result will follow...",
+	"test.result":          "🧪 Test send result",
+	"test.phone":           "🔹 Number: `%s`",
+	"test.pattern":         "🔹 Pattern code: `%s`",
+	"test.failed":          "❌ Send failed: %s",
+	"test.success":         "✅ Sent successfully",
+	"test.message_id":      "🔹 Message ID: `%d`",
+
+	// Audit log
+	"button.audit":                "📜 Change history",
+	"button.audit_export":         "📤 Download JSONL file",
+	"audit.title":                 "📜 Last %d changes:",
+	"audit.empty":                 "No changes have been recorded.",
+	"audit.export_failed":         "❌ Could not export the change history",
+	"audit.action.pattern_change": "Pattern change",
+	"audit.action.sms_pause":      "SMS paused",
+	"audit.action.sms_resume":     "SMS resumed",
+	"audit.action.admins_change":  "Admins changed",
+	"audit.action.sms_enabled":    "SMS sending setting",
+	"audit.action.test_sms":       "Test SMS",
+	"audit.action.optout_add":     "Number opted out",
+	"audit.action.optout_remove":  "Opt-out removed",
+	"audit.action.optout_import":  "Opt-out list imported",
+
+	// Pattern change notifications
+	"pattern.broadcast":      "🔔 %s changed the active pattern",
+	"pattern.broadcast_from": "🔹 From: %s `%s`",
+	"pattern.broadcast_to":   "🔹 To: %s `%s`",
+	"button.undo_pattern":    "↩️ Undo (within %d minutes)",
+	"pattern.undo_expired":   "⌛ This change can no longer be undone: the window has passed or the pattern changed again",
+	"pattern.undone":         "↩️ Pattern reverted to %s `%s`",
+	"pattern.undo_broadcast": "↩️ %s undid the pattern change\n🔹 Active pattern: %s `%s`",
+
+	// Opt-out list
+	"button.optout":            "🚫 Opt-out list",
+	"button.optout_add":        "➕ Add number",
+	"button.optout_export":     "📤 Download CSV file",
+	"optout.title":             "🚫 Opt-out list (%d numbers)",
+	"optout.empty":             "No numbers have opted out.",
+	"optout.recent":            "Most recent %d:",
+	"optout.hint":              "➕ Add: /optout <number> [reason]",
+	"optout.owner_hint":        "➖ Remove: /optin <number>",
+	"optout.ask":               "🚫 Send the mobile number that should no longer receive SMS, optionally followed by a reason:",
+	"optout.added":             "✅ %s will no longer receive SMS",
+	"optout.already":           "⚠️ %s is already on the opt-out list",
+	"optout.removed":           "✅ %s removed from the opt-out list",
+	"optout.not_listed":        "⚠️ %s is not on the opt-out list",
+	"optout.optin_usage":       "❌ Usage: /optin <mobile number>",
+	"optout.save_failed":       "❌ Could not save the opt-out list",
+	"optout.export_failed":     "❌ Could not export the opt-out list",
+	"optout.source.bot":        "bot",
+	"optout.source.api":        "API",
+	"optout.source.csv_import": "CSV import",
+	"optout.source.keyword":    "customer message",
+
+	// Follow-up sequences
+	"button.drip":                 "💧 Follow-up SMS",
+	"drip.title":                  "💧 Leads receiving follow-ups (%d)",
+	"drip.disabled":               "📵 Follow-ups are disabled in the configuration (drip.enabled: false); scheduled steps wait until they are enabled",
+	"drip.empty":                  "No lead has follow-ups scheduled.",
+	"drip.recent":                 "Most recent %d:",
+	"drip.next_step":              "Step %d of %d at %s",
+	"drip.hint":                   "🛒 Mark a purchase: /converted <number>",
+	"drip.converted_usage":        "❌ Usage: /converted <mobile number>",
+	"drip.not_enrolled":           "⚠️ %s has no follow-ups scheduled",
+	"drip.converted":              "🛒 %s marked as converted; %d follow-up SMS cancelled",
+	"drip.lookup_title":           "💧 Follow-up sequence `%s`: %s",
+	"drip.daily_pattern":          "daily pattern",
+	"drip.status.active":          "in progress",
+	"drip.status.completed":       "completed",
+	"drip.status.converted":       "stopped, lead converted",
+	"drip.status.cancelled":       "stopped, number opted out",
+	"drip.step.pending":           "   ⏳ %d. `%s` — due %s",
+	"drip.step.sent":              "   ✅ %d. `%s` — sent %s",
+	"drip.step.failed":            "   ❌ %d. `%s` — failed (due %s)",
+	"drip.step.skipped":           "   ⏭️ %d. `%s` — skipped (was due %s)",
+	"button.converted":            "🛒 Mark as converted",
+	"audit.action.lead_converted": "Lead converted",
+}
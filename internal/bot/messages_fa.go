@@ -0,0 +1,200 @@
+package bot
+
+// messagesFa is the Persian message bundle
+var messagesFa = map[string]string{
+	// Main menu
+	"menu.title":             "🤖 ربات مدیریت پترن‌های SMS\n👋 سلام ! خوش آمدید\n🔒 دسترسی امنیتی فعال\n\nلطفاً یکی از گزینه‌های زیر را انتخاب کنید:",
+	"button.current_pattern": "📱 پترن امروز",
+	"button.next_pattern":    "➡️ برو به پترن بعدی",
+	"button.list_patterns":   "📋 لیست پترن‌ها",
+	"button.list_admins":     "👥 لیست ادمین‌ها",
+	"button.stats":           "📊 آمار",
+	"button.lookup_phone":    "🔎 سوابق شماره",
+	"button.sms_switch":      "⏯️ توقف/ادامه پیامک",
+	"button.test_sms":        "🧪 ارسال پیامک تست",
+	"button.language":        "🌐 زبان / Language",
+	"button.resume":          "▶️ ادامه ارسال",
+
+	// Access control
+	"auth.unauthorized":      "❌ دسترسی غیرمجاز",
+	"auth.owner_only":        "🔒 این عملیات فقط برای مالک ربات مجاز است",
+	"standalone.server_only": "⚠️ این عملیات در ربات مستقل در دسترس نیست، چون وضعیت لحظه‌ای سرور وب‌هوک را تغییر می‌دهد. از ربات داخل سرور (telegram.embedded: true) استفاده کنید، یا برای تغییر الگو مقدار sms.patterns.current را در فایل پیکربندی ویرایش کنید.",
+
+	// Language
+	"language.choose":      "🌐 زبان ربات را انتخاب کنید:",
+	"language.name.fa":     "فارسی",
+	"language.name.en":     "English",
+	"language.changed":     "✅ زبان ربات به فارسی تغییر کرد",
+	"language.save_failed": "⚠️ ذخیره زبان انتخاب‌شده ناموفق بود؛ پس از راه‌اندازی مجدد زبان قبلی استفاده می‌شود",
+
+	// Admins
+	"admins.title": "👥 لیست ادمین‌های سیستم:",
+	"admins.total": "📊 تعداد کل ادمین‌ها: %d",
+
+	// Patterns
+	"pattern.group.0":       "هیچ پترنی تنظیم نشده",
+	"pattern.group.1":       "گروه اول",
+	"pattern.group.2":       "گروه دوم",
+	"pattern.group.3":       "گروه سوم",
+	"pattern.group.4":       "گروه چهارم",
+	"pattern.current.title": "📱 پترن فعلی:",
+	"pattern.group":         "🔹 گروه: %s",
+	"pattern.new_group":     "🔹 گروه جدید: %s",
+	"pattern.number":        "🔹 شماره: %d از %d",
+	"pattern.code":          "🔹 کد پترن: `%s`",
+	"pattern.last_changed":  "⏰ آخرین تغییر: %s",
+	"pattern.changed_by":    "👤 تغییر توسط: %s",
+	"pattern.changed.title": "✅ پترن تغییر کرد!",
+	"pattern.changed_at":    "⏰ زمان تغییر: %s",
+	"patterns.title":        "📋 لیست تمام پترن‌ها:",
+	"patterns.current":      "✅ فعلی",
+
+	// Stats
+	"stats.title":         "📊 آمار سیستم:",
+	"stats.today":         "📅 امروز",
+	"stats.last7":         "🗓️ ۷ روز گذشته",
+	"stats.last30":        "🗓️ ۳۰ روز گذشته",
+	"stats.webhooks":      "📨 رویدادهای وب‌هوک: %d",
+	"stats.leads":         "📥 لیدها: %d",
+	"stats.sms_sent":      "✅ پیامک ارسال‌شده: %d",
+	"stats.sms_duplicate": "⏭️ مسدود به دلیل تکرار: %d",
+	"stats.sms_failed":    "❌ ارسال ناموفق: %d",
+	"stats.sms_timeout":   "⏱️ اتمام مهلت ارسال: %d",
+	"stats.sms_limited":   "🚧 محدود به دلیل سقف ارسال: %d",
+	"stats.sms_opted_out": "🚫 ارسال نشده به دلیل لغو اشتراک: %d",
+	"stats.updated":       "⏰ به‌روزرسانی: %s",
+
+	// Daily report
+	"report.title":           "📊 گزارش روزانه - %s",
+	"report.leads":           "📥 لیدهای دریافتی: %d",
+	"report.valid_phones":    "📱 شماره‌های معتبر: %d",
+	"report.invalid_phones":  "⚠️ شماره‌های نامعتبر: %d",
+	"report.patterns_used":   "🧩 پترن‌های استفاده‌شده:",
+	"report.current_pattern": "📌 پترن فعلی: %s `%s`",
+	"report.credit":          "💰 اعتبار باقی‌مانده: %s ریال",
+	"report.credit_unknown":  "💰 اعتبار باقی‌مانده: نامشخص",
+
+	// Phone lookup
+	"phone.invalid":           "❌ شماره وارد شده معتبر نیست: %s",
+	"status.unknown":          "نامشخص",
+	"lookup.ask":              "🔎 شماره موبایل مورد نظر را ارسال کنید:\n(مثال: 09121234567 یا +989121234567)",
+	"lookup.title":            "🔎 سوابق شماره `%s`",
+	"lookup.empty":            "هیچ سابقه‌ای برای این شماره ثبت نشده است.",
+	"lookup.truncated":        "⚠️ فقط %d مورد آخر از %d مورد نمایش داده می‌شود",
+	"lookup.lead":             "📥 %s — لید دریافت شد",
+	"lookup.lead_details":     "   پلتفرم: `%s` | کاربر: `%s` | لید: `%s`",
+	"lookup.sent":             "✅ %s — پیامک ارسال شد",
+	"lookup.test_sent":        "🧪 %s — پیامک تست ارسال شد (توسط %s)",
+	"lookup.sent_details":     "   پترن: `%s` | شناسه پیام: `%d`",
+	"lookup.delivery":         "   وضعیت تحویل: %s",
+	"lookup.duplicate":        "⏭️ %s — ارسال مسدود شد (قبلاً امروز ارسال شده)\n   کاربر: `%s`",
+	"lookup.queued":           "📥 %s — به دلیل توقف ارسال در صف قرار گرفت",
+	"lookup.deferred":         "🌙 %s — تا پایان ساعات سکوت در صف قرار گرفت",
+	"lookup.failed":           "❌ %s — ارسال ناموفق",
+	"lookup.timeout":          "⏱️ %s — مهلت ارسال به پایان رسید",
+	"lookup.limited":          "🚧 %s — ارسال به دلیل سقف ارسال به این شماره انجام نشد\n   %s",
+	"lookup.opted_out":        "🚫 %s — ارسال نشد، این شماره لغو اشتراک کرده است",
+	"lookup.opted_out_status": "🚫 لغو اشتراک از %s - پیامکی ارسال نمی‌شود",
+	"lookup.test_failed":      "🧪 %s — پیامک تست ناموفق (توسط %s)",
+	"lookup.failed_details":   "   پترن: `%s` | خطا: %s",
+
+	// SMS kill switch
+	"switch.title":          "⏯️ وضعیت ارسال پیامک:",
+	"switch.disabled":       "📵 ارسال پیامک در تنظیمات غیرفعال است (`sms.enabled: false`)",
+	"switch.paused":         "⏸️ متوقف شده",
+	"switch.by":             "👤 توسط: %s",
+	"switch.since":          "⏰ از: %s",
+	"switch.no_auto_resume": "🔁 ادامه خودکار: ندارد",
+	"switch.auto_resume":    "🔁 ادامه خودکار: %s",
+	"switch.running":        "▶️ در حال ارسال",
+	"switch.quiet":          "🌙 ساعات سکوت: پیامک لیدها تا %s در صف می‌مانند",
+	"switch.queued":         "📥 پیامک‌های در صف: %d",
+	"pause.manual":          "⏸️ تا اطلاع ثانوی",
+	"pause.1h":              "⏸️ ۱ ساعت",
+	"pause.3h":              "⏸️ ۳ ساعت",
+	"pause.12h":             "⏸️ ۱۲ ساعت",
+
+	// Test SMS
+	"test.no_patterns":     "❌ هیچ پترنی تنظیم نشده است",
+	"test.choose":          "🧪 ارسال پیامک تست\n\nپترن مورد نظر را انتخاب کنید:",
+	"test.invalid_pattern": "❌ پترن انتخاب شده معتبر نیست",
+	"test.ask_phone":       "🧪 پترن `%s` انتخاب شد.\n\n📱 شماره موبایلی که پیامک تست به آن ارسال شود را وارد کنید:",
+	"test.sending":         "⏳ در حال ارسال پیامک تست، نتیجه اعلام می‌شود...",
+	"test.result":          "🧪 نتیجه ارسال تست",
+	"test.phone":           "🔹 شماره: `%s`",
+	"test.pattern":         "🔹 کد پترن: `%s`",
+	"test.failed":          "❌ ارسال ناموفق: %s",
+	"test.success":         "✅ ارسال موفق",
+	"test.message_id":      "🔹 شناسه پیام: `%d`",
+
+	// Audit log
+	"button.audit":                "📜 تاریخچه تغییرات",
+	"button.audit_export":         "📤 دریافت فایل JSONL",
+	"audit.title":                 "📜 %d تغییر اخیر:",
+	"audit.empty":                 "هیچ تغییری ثبت نشده است.",
+	"audit.export_failed":         "❌ تهیه خروجی تاریخچه ناموفق بود",
+	"audit.action.pattern_change": "تغییر پترن",
+	"audit.action.sms_pause":      "توقف ارسال پیامک",
+	"audit.action.sms_resume":     "ادامه ارسال پیامک",
+	"audit.action.admins_change":  "تغییر ادمین‌ها",
+	"audit.action.sms_enabled":    "تنظیم ارسال پیامک",
+	"audit.action.test_sms":       "ارسال پیامک تست",
+	"audit.action.optout_add":     "لغو اشتراک شماره",
+	"audit.action.optout_remove":  "حذف از لیست لغو اشتراک",
+	"audit.action.optout_import":  "ورود لیست لغو اشتراک",
+
+	// Pattern change notifications
+	"pattern.broadcast":      "🔔 %s پترن فعال را تغییر داد",
+	"pattern.broadcast_from": "🔹 از: %s `%s`",
+	"pattern.broadcast_to":   "🔹 به: %s `%s`",
+	"button.undo_pattern":    "↩️ بازگردانی (تا %d دقیقه)",
+	"pattern.undo_expired":   "⌛ مهلت بازگردانی این تغییر تمام شده یا پترن دوباره تغییر کرده است",
+	"pattern.undone":         "↩️ پترن به %s `%s` بازگردانده شد",
+	"pattern.undo_broadcast": "↩️ %s تغییر پترن را لغو کرد\n🔹 پترن فعال: %s `%s`",
+
+	// Opt-out list
+	"button.optout":            "🚫 لیست لغو اشتراک",
+	"button.optout_add":        "➕ افزودن شماره",
+	"button.optout_export":     "📤 دریافت فایل CSV",
+	"optout.title":             "🚫 لیست لغو اشتراک (%d شماره)",
+	"optout.empty":             "هیچ شماره‌ای لغو اشتراک نکرده است.",
+	"optout.recent":            "%d مورد آخر:",
+	"optout.hint":              "➕ افزودن: /optout <شماره> [دلیل]",
+	"optout.owner_hint":        "➖ حذف: /optin <شماره>",
+	"optout.ask":               "🚫 شماره موبایلی که دیگر نباید پیامک دریافت کند را ارسال کنید (در صورت تمایل همراه با دلیل):",
+	"optout.added":             "✅ از این پس به %s پیامکی ارسال نمی‌شود",
+	"optout.already":           "⚠️ %s از قبل در لیست لغو اشتراک است",
+	"optout.removed":           "✅ %s از لیست لغو اشتراک حذف شد",
+	"optout.not_listed":        "⚠️ %s در لیست لغو اشتراک نیست",
+	"optout.optin_usage":       "❌ نحوه استفاده: /optin <شماره موبایل>",
+	"optout.save_failed":       "❌ ذخیره لیست لغو اشتراک انجام نشد",
+	"optout.export_failed":     "❌ خروجی گرفتن از لیست لغو اشتراک انجام نشد",
+	"optout.source.bot":        "ربات",
+	"optout.source.api":        "API",
+	"optout.source.csv_import": "فایل CSV",
+	"optout.source.keyword":    "پیام مشتری",
+
+	// Follow-up sequences
+	"button.drip":                 "💧 پیامک‌های پیگیری",
+	"drip.title":                  "💧 لیدهای در حال پیگیری (%d)",
+	"drip.disabled":               "📵 پیامک‌های پیگیری در تنظیمات غیرفعال است (drip.enabled: false)؛ مراحل زمان‌بندی‌شده تا فعال شدن منتظر می‌مانند",
+	"drip.empty":                  "هیچ لیدی پیامک پیگیری زمان‌بندی‌شده ندارد.",
+	"drip.recent":                 "%d مورد آخر:",
+	"drip.next_step":              "مرحله %d از %d در %s",
+	"drip.hint":                   "🛒 ثبت خرید: /converted <شماره>",
+	"drip.converted_usage":        "❌ نحوه استفاده: /converted <شماره موبایل>",
+	"drip.not_enrolled":           "⚠️ برای %s پیامک پیگیری زمان‌بندی نشده است",
+	"drip.converted":              "🛒 خرید %s ثبت شد؛ %d پیامک پیگیری لغو شد",
+	"drip.lookup_title":           "💧 توالی پیگیری `%s`: %s",
+	"drip.daily_pattern":          "پترن روز",
+	"drip.status.active":          "در حال اجرا",
+	"drip.status.completed":       "تکمیل شده",
+	"drip.status.converted":       "متوقف شد، لید خرید کرد",
+	"drip.status.cancelled":       "متوقف شد، شماره لغو اشتراک کرد",
+	"drip.step.pending":           "   ⏳ %d. `%s` — موعد %s",
+	"drip.step.sent":              "   ✅ %d. `%s` — ارسال شده %s",
+	"drip.step.failed":            "   ❌ %d. `%s` — ناموفق (موعد %s)",
+	"drip.step.skipped":           "   ⏭️ %d. `%s` — لغو شده (موعد %s)",
+	"button.converted":            "🛒 ثبت خرید",
+	"audit.action.lead_converted": "ثبت خرید لید",
+}
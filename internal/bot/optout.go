@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/optout"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// inputOptOut waits for "<phone> [reason]" to add to the opt-out list
+	inputOptOut = "optout_add"
+	// recentOptOuts is how many opt-outs the bot lists
+	recentOptOuts = 15
+)
+
+// showOptOuts shows the size of the opt-out list and its most recent entries
+func (b *Bot) showOptOuts(c *Context) {
+	entries := b.smsService.OptOuts().Entries()
+	text := c.T("optout.title", len(entries)) + "\n\n"
+
+	if len(entries) == 0 {
+		text += c.T("optout.empty") + "\n"
+	}
+	if len(entries) > recentOptOuts {
+		text += c.T("optout.recent", recentOptOuts) + "\n"
+		entries = entries[:recentOptOuts]
+	}
+	for _, entry := range entries {
+		text += formatOptOut(c.Admin.Language, entry) + "\n"
+	}
+
+	text += "\n" + c.T("optout.hint")
+	if c.Admin.Owner {
+		text += "\n" + c.T("optout.owner_hint")
+	}
+
+	// Plain text: reasons are free-form
+	msg := tgbotapi.NewMessage(c.ChatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.optout_add"), "optout_add"),
+			tgbotapi.NewInlineKeyboardButtonData(c.T("button.optout_export"), "optout_export"),
+		),
+	)
+	b.send(msg)
+}
+
+// askOptOutPhone prompts the admin to type the phone number to opt out
+func (b *Bot) askOptOutPhone(c *Context) {
+	b.awaitInput(c.ChatID, inputOptOut, "")
+
+	b.sendText(c.ChatID, c.T("optout.ask"))
+}
+
+// optOutCommand handles "/optout <phone> [reason]", prompting when no number is given
+func (b *Bot) optOutCommand(c *Context) {
+	if c.Args == "" {
+		b.askOptOutPhone(c)
+		return
+	}
+
+	c.Text = c.Args
+	b.addOptOut(c)
+}
+
+// addOptOut adds the phone number in "<phone> [reason]" to the opt-out list
+func (b *Bot) addOptOut(c *Context) {
+	phone, reason, _ := strings.Cut(strings.TrimSpace(c.Text), " ")
+
+	added, err := b.smsService.OptOuts().Add(optout.Entry{
+		Phone:   phone,
+		AddedBy: c.Admin.Name,
+		Source:  optout.SourceBot,
+		Reason:  strings.TrimSpace(reason),
+	})
+	if errors.Is(err, optout.ErrInvalidPhone) {
+		b.sendText(c.ChatID, c.T("phone.invalid", phone))
+		return
+	}
+	if err != nil {
+		b.logger.Error("Failed to add opt-out", "phone", phone, "error", err)
+		b.sendText(c.ChatID, c.T("optout.save_failed"))
+		return
+	}
+
+	phone = utils.NormalizeIranianPhone(phone)
+	if !added {
+		b.sendText(c.ChatID, c.T("optout.already", phone))
+		return
+	}
+
+	b.recordAudit(c, audit.ActionOptOutAdd, "", phone, strings.TrimSpace(reason))
+	b.sendText(c.ChatID, c.T("optout.added", phone))
+}
+
+// optInCommand handles "/optin <phone>", taking a number off the opt-out list
+func (b *Bot) optInCommand(c *Context) {
+	phone := utils.NormalizeIranianPhone(c.Args)
+	if phone == "" {
+		b.sendText(c.ChatID, c.T("optout.optin_usage"))
+		return
+	}
+
+	removed, err := b.smsService.OptOuts().Remove(phone)
+	if err != nil {
+		b.logger.Error("Failed to remove opt-out", "phone", phone, "error", err)
+		b.sendText(c.ChatID, c.T("optout.save_failed"))
+		return
+	}
+	if !removed {
+		b.sendText(c.ChatID, c.T("optout.not_listed", phone))
+		return
+	}
+
+	b.recordAudit(c, audit.ActionOptOutRemove, phone, "", "")
+	b.sendText(c.ChatID, c.T("optout.removed", phone))
+}
+
+// exportOptOuts sends the whole opt-out list as a CSV document
+func (b *Bot) exportOptOuts(c *Context) {
+	optOuts := b.smsService.OptOuts()
+	if optOuts.Len() == 0 {
+		b.sendText(c.ChatID, c.T("optout.empty"))
+		return
+	}
+
+	data, err := optOuts.ExportCSV()
+	if err != nil {
+		b.logger.Error("Failed to export opt-out list", "error", err)
+		b.sendText(c.ChatID, c.T("optout.export_failed"))
+		return
+	}
+
+	name := "optout-" + utils.TehranNow().Format("20060102-1504") + ".csv"
+	b.send(tgbotapi.NewDocument(c.ChatID, tgbotapi.FileBytes{Name: name, Bytes: data}))
+}
+
+// formatOptOut renders a single opt-out entry
+func formatOptOut(lang Lang, entry optout.Entry) string {
+	text := "🔹 " + entry.Phone + " — " + formatDateTime(lang, entry.AddedAt) + " (" + tr(lang, "optout.source."+entry.Source) + ")"
+	if entry.Reason != "" {
+		text += "\n   " + entry.Reason
+	}
+	return text
+}
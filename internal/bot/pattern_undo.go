@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"strconv"
+	"time"
+
+	"novinhub-webhook/internal/audit"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// patternSwitch is a pattern change made from the bot that may still be undone
+type patternSwitch struct {
+	id            int64
+	previousIndex int // 1-based, as returned by GetCurrentPatternInfo
+	newIndex      int
+	at            time.Time
+}
+
+// undoWindow returns how long a pattern change can be undone; zero disables undo
+func (b *Bot) undoWindow() time.Duration {
+	return time.Duration(b.config.Telegram.UndoWindowMinutes) * time.Minute
+}
+
+// rememberPatternSwitch makes a change the one the undo button reverts and returns its ID
+func (b *Bot) rememberPatternSwitch(previousIndex, newIndex int, at time.Time) int64 {
+	b.undoMu.Lock()
+	defer b.undoMu.Unlock()
+
+	b.switchSeq++
+	b.lastSwitch = patternSwitch{id: b.switchSeq, previousIndex: previousIndex, newIndex: newIndex, at: at}
+	return b.switchSeq
+}
+
+// takePatternSwitch returns the change with the given ID if it can still be undone, consuming it
+func (b *Bot) takePatternSwitch(id int64) (patternSwitch, bool) {
+	b.undoMu.Lock()
+	defer b.undoMu.Unlock()
+
+	last := b.lastSwitch
+	if last.id == 0 || last.id != id || time.Since(last.at) > b.undoWindow() {
+		return patternSwitch{}, false
+	}
+
+	b.lastSwitch = patternSwitch{}
+	return last, true
+}
+
+// undoKeyboard returns the undo button for a pattern change, or nil when undo is disabled
+func (b *Bot) undoKeyboard(lang Lang, id int64) interface{} {
+	if b.undoWindow() <= 0 || id == 0 {
+		return nil
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "button.undo_pattern", b.config.Telegram.UndoWindowMinutes), "undo_pattern:"+strconv.FormatInt(id, 10)),
+		),
+	)
+}
+
+// broadcastPatternSwitch tells every other admin who changed the pattern, offering an undo button
+func (b *Bot) broadcastPatternSwitch(c *Context, previous string, previousIndex int, pattern string, index int, at time.Time, id int64) {
+	actor := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, c.Admin.Name)
+
+	for _, admin := range b.admins() {
+		if admin.ID == c.Admin.ID {
+			continue
+		}
+
+		lang := admin.Language
+		text := tr(lang, "pattern.broadcast", actor) + "\n\n"
+		text += tr(lang, "pattern.broadcast_from", groupName(lang, previousIndex), previous) + "\n"
+		text += tr(lang, "pattern.broadcast_to", groupName(lang, index), pattern) + "\n"
+		text += tr(lang, "pattern.changed_at", formatDateTime(lang, at))
+
+		b.notifyAdmin(admin, text, b.undoKeyboard(lang, id))
+	}
+}
+
+// notifyAdmin sends a Markdown message to an admin's private chat
+func (b *Bot) notifyAdmin(admin Admin, text string, markup interface{}) {
+	msg := tgbotapi.NewMessage(admin.ID, text)
+	msg.ParseMode = "Markdown"
+	if markup != nil {
+		msg.ReplyMarkup = markup
+	}
+
+	if _, err := b.sender.Send(msg); err != nil {
+		b.logger.Warn("Failed to notify admin", "user_id", admin.ID, "admin_name", admin.Name, "error", err)
+	}
+}
+
+// undoPatternSwitch reverts the pattern change named by the callback argument
+func (b *Bot) undoPatternSwitch(c *Context) {
+	id, err := strconv.ParseInt(c.Args, 10, 64)
+	if err != nil {
+		b.logger.Warn("Invalid pattern undo ID", "value", c.Args)
+		return
+	}
+
+	last, ok := b.takePatternSwitch(id)
+	// Someone may have switched again with a command other than the bot's next button
+	if _, current, _ := b.config.GetCurrentPatternInfo(); ok && current != last.newIndex {
+		ok = false
+	}
+	if !ok {
+		b.sendText(c.ChatID, c.T("pattern.undo_expired"))
+		return
+	}
+
+	before, _, _ := b.config.GetCurrentPatternInfo()
+	if err := b.config.SetPattern(last.previousIndex-1, c.Admin.Name); err != nil {
+		b.logger.Error("Failed to undo pattern change", "error", err)
+		b.sendText(c.ChatID, c.T("pattern.undo_expired"))
+		return
+	}
+	pattern, index, _ := b.config.GetCurrentPatternInfo()
+	b.savePatternChange()
+
+	b.recordAudit(c, audit.ActionPatternChange, before, pattern, "undo of "+groupName(LangEn, last.newIndex))
+	b.logger.Info("↩️ Pattern change undone", "user_id", c.Admin.ID, "admin_name", c.Admin.Name, "pattern", pattern)
+
+	b.sendMarkdown(c.ChatID, c.T("pattern.undone", groupName(c.Admin.Language, index), pattern))
+
+	actor := tgbotapi.EscapeText(tgbotapi.ModeMarkdown, c.Admin.Name)
+	for _, admin := range b.admins() {
+		if admin.ID == c.Admin.ID {
+			continue
+		}
+		b.notifyAdmin(admin, tr(admin.Language, "pattern.undo_broadcast", actor, groupName(admin.Language, index), pattern), nil)
+	}
+}
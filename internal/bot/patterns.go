@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"strconv"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *Bot) showCurrentPattern(c *Context) {
+	pattern, index, _ := b.config.GetCurrentPatternInfo()
+
+	text := c.T("pattern.current.title") + "\n\n"
+	text += c.T("pattern.group", groupName(c.Admin.Language, index)) + "\n"
+	text += c.T("pattern.number", index, b.config.PatternCount()) + "\n"
+	text += c.T("pattern.code", pattern) + "\n\n"
+	text += patternChangeText(c.Admin.Language, b.config.LastPatternChange())
+
+	b.sendMarkdown(c.ChatID, text)
+}
+
+func (b *Bot) nextPattern(c *Context) {
+	previous, previousIndex, _ := b.config.GetCurrentPatternInfo()
+	pattern, index, _ := b.config.NextPattern(c.Admin.Name)
+	change := b.config.LastPatternChange()
+	b.savePatternChange()
+
+	b.recordAudit(c, audit.ActionPatternChange, previous, pattern, groupName(LangEn, index))
+	undoID := b.rememberPatternSwitch(previousIndex, index, change.At)
+
+	text := c.T("pattern.changed.title") + "\n\n"
+	text += c.T("pattern.new_group", groupName(c.Admin.Language, index)) + "\n"
+	text += c.T("pattern.number", index, b.config.PatternCount()) + "\n"
+	text += c.T("pattern.code", pattern) + "\n\n"
+	text += c.T("pattern.changed_at", formatDateTime(c.Admin.Language, change.At))
+
+	msg := tgbotapi.NewMessage(c.ChatID, text)
+	msg.ParseMode = "Markdown"
+	if keyboard := b.undoKeyboard(c.Admin.Language, undoID); keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+	b.send(msg)
+
+	b.broadcastPatternSwitch(c, previous, previousIndex, pattern, index, change.At, undoID)
+}
+
+func (b *Bot) showPatternsList(c *Context) {
+	patterns := b.config.GetPatternsList()
+
+	text := c.T("patterns.title") + "\n\n"
+
+	for _, p := range patterns {
+		status := "❌"
+		if p["is_current"].(bool) {
+			status = c.T("patterns.current")
+		}
+
+		index := p["index"].(int)
+		text += status + " " + groupName(c.Admin.Language, index) + " (" + strconv.Itoa(index) + "): `" + p["pattern"].(string) + "`\n"
+	}
+
+	text += "\n" + patternChangeText(c.Admin.Language, b.config.LastPatternChange())
+
+	b.sendMarkdown(c.ChatID, text)
+}
+
+// patternChangeText renders when and by whom the active pattern last changed
+func patternChangeText(lang Lang, change config.PatternChange) string {
+	if change.At.IsZero() && change.By == "" {
+		return tr(lang, "pattern.last_changed", tr(lang, "status.unknown"))
+	}
+
+	when, by := tr(lang, "status.unknown"), tr(lang, "status.unknown")
+	if !change.At.IsZero() {
+		when = formatDateTime(lang, change.At)
+	}
+	if change.By != "" {
+		by = tgbotapi.EscapeText(tgbotapi.ModeMarkdown, change.By)
+	}
+	return tr(lang, "pattern.last_changed", when) + "\n" + tr(lang, "pattern.changed_by", by)
+}
+
+// savePatternChange saves the active pattern with when and by whom it was made active
+func (b *Bot) savePatternChange() {
+	change := b.config.LastPatternChange()
+	saved := storedPatternChange{Pattern: b.config.GetCurrentPattern(), At: change.At, By: change.By}
+	if err := b.state.setPatternChange(saved); err != nil {
+		b.logger.Warn("⚠️ Failed to save pattern change - its time and author are lost on restart", "error", err)
+	}
+}
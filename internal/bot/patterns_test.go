@@ -0,0 +1,57 @@
+package bot_test
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"novinhub-webhook/internal/bot"
+	"novinhub-webhook/internal/bot/bottest"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/pkg/logger"
+)
+
+// restartedBot starts a bot with a fresh config whose active pattern index is current, reading stateFile
+func restartedBot(t *testing.T, stateFile string, current int) *bottest.Harness {
+	t.Helper()
+
+	cfg := bottest.Config(config.TelegramAdmin{ID: ownerID, Name: "Owner", Owner: true, Language: "en"})
+	cfg.SMS.Patterns.Current = current
+	h := bottest.New(cfg)
+
+	log := logger.New()
+	log.SetOutput(io.Discard)
+	cfg.Telegram.StateFile = stateFile
+	h.Bot = bot.New(h.Sender, cfg, log, h.Stats, h.SMS, h.Audit, h.Drip)
+	return h
+}
+
+func TestPatternChangeSurvivesRestart(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "bot_state.json")
+
+	h := restartedBot(t, stateFile, 0)
+	h.Press(ownerID, "next_pattern")
+	switched := h.Config.LastPatternChange()
+
+	h = restartedBot(t, stateFile, 1)
+	restored := h.Config.LastPatternChange()
+	if restored.By != "Owner" || !restored.At.Equal(switched.At) {
+		t.Fatalf("expected the switch by Owner at %v to be restored, got %+v", switched.At, restored)
+	}
+}
+
+func TestPatternChangeUnknownAfterConfigChange(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "bot_state.json")
+
+	h := restartedBot(t, stateFile, 0)
+	h.Press(ownerID, "next_pattern")
+
+	// The config file now activates a different pattern than the one switched to
+	h = restartedBot(t, stateFile, 2)
+	if change := h.Config.LastPatternChange(); change != (config.PatternChange{}) {
+		t.Fatalf("expected an unknown change, got %+v", change)
+	}
+
+	h.Press(ownerID, "current_pattern")
+	h.AssertLastMessageContains(t, "Last changed: unknown")
+}
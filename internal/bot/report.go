@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// StartDailyReport schedules the report on the previous day sent to all admins
+func (b *Bot) StartDailyReport() {
+	if !b.config.Report.Enabled {
+		b.logger.Info("Daily report disabled")
+		return
+	}
+
+	hour, minute, err := parseReportTime(b.config.Report.Time)
+	if err != nil {
+		b.logger.Error("Invalid daily report time - report disabled", "time", b.config.Report.Time, "error", err)
+		return
+	}
+
+	go func() {
+		for {
+			next := nextReportTime(utils.TehranNow(), hour, minute)
+			b.logger.Info("🗓️ Daily report scheduled", "at", next.Format("2006-01-02 15:04:05"))
+
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(time.Until(next)):
+			}
+			b.sendDailyReport()
+		}
+	}()
+}
+
+// parseReportTime parses an HH:MM time of day
+func parseReportTime(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("expected HH:MM: %w", err)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// nextReportTime returns the next occurrence of hour:minute in Tehran time
+func nextReportTime(now time.Time, hour, minute int) time.Time {
+	next := utils.StartOfDay(now).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// sendDailyReport builds the report on the previous full day and sends it to every admin
+func (b *Bot) sendDailyReport() {
+	today := utils.StartOfDay(utils.TehranNow())
+	summary := b.stats.Summarize(today.AddDate(0, 0, -1), today)
+
+	credit, err := b.smsService.GetCredit()
+	if err != nil {
+		b.logger.Warn("Failed to fetch SMS credit for daily report", "error", err)
+	}
+
+	// Each admin gets the report in their own language
+	for _, admin := range b.admins() {
+		msg := tgbotapi.NewMessage(admin.ID, b.buildDailyReport(admin.Language, summary, credit, err))
+		msg.ParseMode = "Markdown"
+		if _, err := b.sender.Send(msg); err != nil {
+			b.logger.Error("Failed to send daily report", "user_id", admin.ID, "admin_name", admin.Name, "error", err)
+		}
+	}
+
+	b.logger.Info("📊 Daily report sent",
+		"leads", summary.Leads,
+		"sms_sent", summary.SMSSent,
+		"sms_duplicate", summary.SMSDuplicate,
+		"sms_failed", summary.SMSFailed,
+		"sms_timeout", summary.SMSTimedOut,
+		"sms_limited", summary.SMSLimited,
+		"sms_opted_out", summary.SMSOptedOut)
+}
+
+// buildDailyReport renders the daily summary as a bot message
+func (b *Bot) buildDailyReport(lang Lang, summary stats.Summary, credit float64, creditErr error) string {
+	text := tr(lang, "report.title", formatDate(lang, summary.From)) + "\n\n"
+
+	text += tr(lang, "report.leads", summary.Leads) + "\n"
+	for _, platform := range sortedKeys(summary.LeadsByPlatform) {
+		text += "   🔹 `" + platform + "`: " + strconv.Itoa(summary.LeadsByPlatform[platform]) + "\n"
+	}
+	text += tr(lang, "report.valid_phones", summary.ValidPhones) + "\n"
+	text += tr(lang, "report.invalid_phones", summary.InvalidPhones) + "\n\n"
+
+	text += tr(lang, "stats.sms_sent", summary.SMSSent) + "\n"
+	text += tr(lang, "stats.sms_duplicate", summary.SMSDuplicate) + "\n"
+	text += tr(lang, "stats.sms_failed", summary.SMSFailed) + "\n"
+	text += tr(lang, "stats.sms_timeout", summary.SMSTimedOut) + "\n"
+	text += tr(lang, "stats.sms_limited", summary.SMSLimited) + "\n"
+	text += tr(lang, "stats.sms_opted_out", summary.SMSOptedOut) + "\n\n"
+
+	text += tr(lang, "report.patterns_used") + "\n"
+	if len(summary.SentByPattern) == 0 {
+		text += "   —\n"
+	}
+	for _, pattern := range sortedKeys(summary.SentByPattern) {
+		text += "   🔹 `" + pattern + "`: " + strconv.Itoa(summary.SentByPattern[pattern]) + "\n"
+	}
+
+	pattern, index, _ := b.config.GetCurrentPatternInfo()
+	text += tr(lang, "report.current_pattern", groupName(lang, index), pattern) + "\n"
+	text += patternChangeText(lang, b.config.LastPatternChange()) + "\n\n"
+
+	if creditErr != nil {
+		text += tr(lang, "report.credit_unknown")
+	} else {
+		text += tr(lang, "report.credit", strconv.FormatFloat(credit, 'f', 0, 64))
+	}
+
+	return text
+}
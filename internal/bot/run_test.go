@@ -0,0 +1,24 @@
+package bot_test
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestRunHandlesBufferedUpdatesAfterStop(t *testing.T) {
+	h := newHarness()
+
+	// Updates accepted by the webhook handler before the server shut down
+	updates := make(chan tgbotapi.Update, 3)
+	for i := 0; i < cap(updates); i++ {
+		updates <- h.MessageUpdate(adminID, "/start")
+	}
+
+	h.Bot.Stop()
+	h.Bot.Run(updates)
+
+	if got := len(h.Sender.Messages()); got != cap(updates) {
+		t.Fatalf("expected %d replies after Stop, got %d", cap(updates), got)
+	}
+}
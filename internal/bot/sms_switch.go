@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"strconv"
+	"time"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/services"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// pauseDurations are the auto-resume choices offered when pausing (minutes, 0 = manual resume)
+var pauseDurations = []struct {
+	labelKey string
+	minutes  int
+}{
+	{"pause.manual", 0},
+	{"pause.1h", 60},
+	{"pause.3h", 180},
+	{"pause.12h", 720},
+}
+
+// showSMSSwitch shows the kill switch state and the actions available to owners
+func (b *Bot) showSMSSwitch(c *Context) {
+	pause := b.smsService.PauseState()
+
+	text := c.T("switch.title") + "\n\n"
+
+	if !b.config.SMSEnabled() {
+		text += c.T("switch.disabled") + "\n\n"
+	}
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if pause.Paused {
+		text += c.T("switch.paused") + "\n"
+		text += c.T("switch.by", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, pause.By)) + "\n"
+		text += c.T("switch.since", formatDateTime(c.Admin.Language, pause.At)) + "\n"
+		if pause.Until.IsZero() {
+			text += c.T("switch.no_auto_resume") + "\n"
+		} else {
+			text += c.T("switch.auto_resume", formatDateTime(c.Admin.Language, pause.Until)) + "\n"
+		}
+
+		keyboard = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(c.T("button.resume"), "sms_resume"),
+			),
+		)
+	} else {
+		text += c.T("switch.running") + "\n"
+		if until, quiet := b.config.QuietHours().QuietUntil(time.Now()); quiet {
+			text += c.T("switch.quiet", formatDateTime(c.Admin.Language, until)) + "\n"
+		}
+
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for _, choice := range pauseDurations {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(c.T(choice.labelKey), "sms_pause:"+strconv.Itoa(choice.minutes)),
+			))
+		}
+		keyboard = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	text += c.T("switch.queued", b.smsService.QueueLen())
+
+	msg := tgbotapi.NewMessage(c.ChatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	b.send(msg)
+}
+
+// pauseSMS pauses SMS sending; the callback argument is the auto-resume delay in minutes
+func (b *Bot) pauseSMS(c *Context) {
+	minutes, err := strconv.Atoi(c.Args)
+	if err != nil || minutes < 0 {
+		b.logger.Warn("Invalid pause duration", "value", c.Args)
+		return
+	}
+
+	before := smsSwitchState(b.smsService.PauseState())
+	b.smsService.Pause(c.Admin.Name, time.Duration(minutes)*time.Minute)
+	b.logger.Info("⏸️ SMS paused from bot", "user_id", c.Admin.ID, "admin_name", c.Admin.Name, "minutes", minutes)
+
+	detail := "manual resume"
+	if minutes > 0 {
+		detail = "auto-resume after " + strconv.Itoa(minutes) + "m"
+	}
+	b.recordAudit(c, audit.ActionSMSPause, before, "paused", detail)
+
+	b.showSMSSwitch(c)
+}
+
+// resumeSMS resumes SMS sending
+func (b *Bot) resumeSMS(c *Context) {
+	before := smsSwitchState(b.smsService.PauseState())
+	b.smsService.Resume(c.Admin.Name)
+	b.logger.Info("▶️ SMS resumed from bot", "user_id", c.Admin.ID, "admin_name", c.Admin.Name)
+
+	b.recordAudit(c, audit.ActionSMSResume, before, "running", "")
+
+	b.showSMSSwitch(c)
+}
+
+// smsSwitchState describes the kill switch state for audit entries
+func smsSwitchState(pause services.PauseState) string {
+	if pause.Paused {
+		return "paused"
+	}
+	return "running"
+}
@@ -0,0 +1,22 @@
+package bot_test
+
+import (
+	"testing"
+
+	"novinhub-webhook/internal/bot/bottest"
+	"novinhub-webhook/internal/config"
+)
+
+func TestSMSSwitchEscapesPausedBy(t *testing.T) {
+	h := bottest.New(bottest.Config(
+		config.TelegramAdmin{ID: ownerID, Name: "night_shift*owner", Owner: true, Language: "en"},
+	))
+
+	h.Press(ownerID, "sms_pause:0")
+
+	msg := h.LastMessage(t)
+	if msg.ParseMode != "Markdown" {
+		t.Fatalf("expected a Markdown message, got parse mode %q", msg.ParseMode)
+	}
+	h.AssertLastMessageContains(t, `night\_shift\*owner`)
+}
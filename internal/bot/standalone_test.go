@@ -0,0 +1,33 @@
+package bot_test
+
+import "testing"
+
+func TestStandaloneRefusesServerState(t *testing.T) {
+	h := newHarness()
+	h.Bot.SetStandalone(true)
+
+	pattern := h.Config.GetCurrentPattern()
+
+	h.Press(ownerID, "next_pattern")
+	h.AssertLastMessageContains(t, "Not available in the standalone bot")
+	if got := h.Config.GetCurrentPattern(); got != pattern {
+		t.Fatalf("standalone bot switched the pattern from %q to %q", pattern, got)
+	}
+
+	h.Press(ownerID, "sms_pause:0")
+	h.AssertLastMessageContains(t, "Not available in the standalone bot")
+	if h.SMS.IsPaused() {
+		t.Fatal("standalone bot paused SMS sending")
+	}
+
+	h.Press(ownerID, "sms_switch")
+	h.AssertLastMessageContains(t, "Not available in the standalone bot")
+}
+
+func TestStandaloneShowsCurrentPattern(t *testing.T) {
+	h := newHarness()
+	h.Bot.SetStandalone(true)
+
+	h.Press(adminID, "current_pattern")
+	h.AssertLastMessageContains(t, h.Config.GetCurrentPattern())
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// botState holds preferences changed from the bot, persisted across restarts
+type botState struct {
+	Languages     map[int64]Lang       `json:"languages,omitempty"`      // Admin ID -> chosen language
+	PatternChange *storedPatternChange `json:"pattern_change,omitempty"` // Last pattern change made from the bot
+}
+
+// storedPatternChange is when and by whom pattern was made active, restored while it stays active
+type storedPatternChange struct {
+	Pattern string    `json:"pattern"`
+	At      time.Time `json:"at"`
+	By      string    `json:"by"`
+}
+
+// stateStore persists botState as a JSON file; an empty path keeps it in memory only
+type stateStore struct {
+	path string
+
+	mu    sync.Mutex
+	state botState
+}
+
+// loadState reads the state file, starting empty if it does not exist yet
+func loadState(path string) (*stateStore, error) {
+	s := &stateStore{
+		path:  path,
+		state: botState{Languages: make(map[int64]Lang)},
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read bot state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return s, fmt.Errorf("failed to parse bot state: %w", err)
+	}
+	if s.state.Languages == nil {
+		s.state.Languages = make(map[int64]Lang)
+	}
+
+	return s, nil
+}
+
+// language returns the language an admin chose in the bot, if any
+func (s *stateStore) language(adminID int64) (Lang, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lang, ok := s.state.Languages[adminID]
+	return lang, ok
+}
+
+// setLanguage stores an admin's language and saves the state
+func (s *stateStore) setLanguage(adminID int64, lang Lang) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.Languages[adminID] = lang
+	return s.saveLocked()
+}
+
+// patternChange returns the last pattern change saved, if any
+func (s *stateStore) patternChange() (storedPatternChange, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.PatternChange == nil {
+		return storedPatternChange{}, false
+	}
+	return *s.state.PatternChange, true
+}
+
+// setPatternChange stores the last pattern change and saves the state
+func (s *stateStore) setPatternChange(change storedPatternChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.PatternChange = &change
+	return s.saveLocked()
+}
+
+// saveLocked writes the state atomically; callers hold mu
+func (s *stateStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bot state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create bot state directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bot state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace bot state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"novinhub-webhook/internal/utils"
+)
+
+func (b *Bot) showStats(c *Context) {
+	now := utils.TehranNow()
+	today := utils.StartOfDay(now)
+
+	periods := []struct {
+		titleKey string
+		from     time.Time
+	}{
+		{"stats.today", today},
+		{"stats.last7", today.AddDate(0, 0, -6)},
+		{"stats.last30", today.AddDate(0, 0, -29)},
+	}
+
+	text := c.T("stats.title") + "\n"
+
+	for _, period := range periods {
+		summary := b.stats.Summarize(period.from, now)
+
+		text += "\n" + c.T(period.titleKey) + "\n"
+		text += c.T("stats.webhooks", summary.Webhooks) + "\n"
+		for _, eventType := range sortedKeys(summary.WebhooksByType) {
+			text += "   🔹 `" + eventType + "`: " + strconv.Itoa(summary.WebhooksByType[eventType]) + "\n"
+		}
+		text += c.T("stats.leads", summary.Leads) + "\n"
+		text += c.T("stats.sms_sent", summary.SMSSent) + "\n"
+		for _, pattern := range sortedKeys(summary.SentByPattern) {
+			text += "   🔹 `" + pattern + "`: " + strconv.Itoa(summary.SentByPattern[pattern]) + "\n"
+		}
+		text += c.T("stats.sms_duplicate", summary.SMSDuplicate) + "\n"
+		text += c.T("stats.sms_failed", summary.SMSFailed) + "\n"
+		text += c.T("stats.sms_timeout", summary.SMSTimedOut) + "\n"
+		text += c.T("stats.sms_limited", summary.SMSLimited) + "\n"
+		text += c.T("stats.sms_opted_out", summary.SMSOptedOut) + "\n"
+	}
+
+	text += "\n" + c.T("stats.updated", formatDateTime(c.Admin.Language, now))
+
+	b.sendMarkdown(c.ChatID, text)
+}
+
+// sortedKeys returns map keys in a stable order for rendering
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
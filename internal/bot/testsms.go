@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// inputTestSMS waits for the phone number that receives a test pattern
+	inputTestSMS = "test_sms"
+	// testSMSTimeout bounds a test send, retries included
+	testSMSTimeout = time.Minute
+)
+
+// chooseTestPattern asks the admin which pattern to send as a test
+func (b *Bot) chooseTestPattern(c *Context) {
+	patterns := b.config.GetPatternsList()
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, p := range patterns {
+		if p["pattern"].(string) == "" {
+			continue
+		}
+		label := groupName(c.Admin.Language, p["index"].(int)) + " (" + p["pattern"].(string) + ")"
+		if p["is_current"].(bool) {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, "test_pattern:"+strconv.Itoa(p["index"].(int))),
+		))
+	}
+
+	if len(rows) == 0 {
+		b.sendText(c.ChatID, c.T("test.no_patterns"))
+		return
+	}
+
+	msg := tgbotapi.NewMessage(c.ChatID, c.T("test.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.send(msg)
+}
+
+// askTestPhone asks for the phone number that should receive the chosen pattern
+func (b *Bot) askTestPhone(c *Context) {
+	pattern, ok := b.testPatternByIndex(c.Args)
+	if !ok {
+		b.sendText(c.ChatID, c.T("test.invalid_pattern"))
+		return
+	}
+
+	b.awaitInput(c.ChatID, inputTestSMS, c.Args)
+
+	b.sendMarkdown(c.ChatID, c.T("test.ask_phone", pattern))
+}
+
+// sendTestSMS sends the chosen pattern to the typed phone number and reports the result when the send finishes
+// The send runs in the background so other admins aren't kept waiting while the provider is retried
+func (b *Bot) sendTestSMS(c *Context) {
+	pattern, ok := b.testPatternByIndex(c.Args)
+	if !ok {
+		b.sendText(c.ChatID, c.T("test.invalid_pattern"))
+		return
+	}
+
+	phone := utils.NormalizeIranianPhone(c.Text)
+	if phone == "" || !utils.IsValidIranianPhone(phone) {
+		b.sendText(c.ChatID, c.T("phone.invalid", c.Text))
+		return
+	}
+
+	b.sendText(c.ChatID, c.T("test.sending"))
+
+	b.background.Add(1)
+	go func() {
+		defer b.background.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testSMSTimeout)
+		defer cancel()
+
+		messageID, err := b.smsService.SendTestSMSContext(ctx, pattern, phone, c.Admin.Name)
+		b.reportTestSMS(c, pattern, phone, messageID, err)
+	}()
+}
+
+// reportTestSMS records a finished test send and tells the admin who asked for it
+func (b *Bot) reportTestSMS(c *Context, pattern, phone string, messageID int64, err error) {
+	detail := "phone " + phone + ", message ID " + strconv.FormatInt(messageID, 10)
+	if err != nil {
+		detail = "phone " + phone + ", failed: " + err.Error()
+	}
+	b.recordAudit(c, audit.ActionTestSMS, "", pattern, detail)
+
+	text := c.T("test.result") + "\n\n"
+	text += c.T("test.phone", phone) + "\n"
+	text += c.T("test.pattern", pattern) + "\n"
+	if err != nil {
+		text += c.T("test.failed", tgbotapi.EscapeText(tgbotapi.ModeMarkdown, err.Error()))
+	} else {
+		text += c.T("test.success") + "\n"
+		text += c.T("test.message_id", messageID)
+	}
+
+	b.sendMarkdown(c.ChatID, text)
+}
+
+// testPatternByIndex resolves a 1-based pattern index chosen in the bot
+func (b *Bot) testPatternByIndex(index string) (string, bool) {
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return "", false
+	}
+	return b.config.PatternAt(i - 1)
+}
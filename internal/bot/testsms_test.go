@@ -0,0 +1,22 @@
+package bot_test
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestTestSMSReportsResultAfterSending(t *testing.T) {
+	h := newHarness()
+
+	h.Press(adminID, "test_pattern:1")
+	h.SendText(adminID, "09121234567")
+	h.AssertLastMessageContains(t, "Sending the test SMS")
+
+	// Run returns once the background send has reported
+	h.Bot.Stop()
+	h.Bot.Run(make(chan tgbotapi.Update))
+
+	h.AssertLastMessageContains(t, "Test send result")
+	h.AssertLastMessageContains(t, "Send failed")
+}
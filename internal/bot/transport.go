@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// telegramSecretHeader carries the secret token Telegram echoes back on every webhook call
+	telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// updatesBuffer matches the buffer size tgbotapi uses for its polling channel
+	updatesBuffer = 100
+	// updateQueueWait is how long a webhook call waits for room in a full update buffer before Telegram is told to retry
+	updateQueueWait = 10 * time.Second
+)
+
+// RouteRegistrar is implemented by the HTTP server the webhook route is mounted on
+type RouteRegistrar interface {
+	Handle(path string, handler http.Handler)
+}
+
+// Updates returns the update channel, using webhook mode when configured and long polling otherwise
+// routes may be nil when no HTTP server is available, which forces long polling
+func (b *Bot) Updates(updater Updater, routes RouteRegistrar) tgbotapi.UpdatesChannel {
+	if b.config.Telegram.Webhook.Enabled() && routes != nil {
+		updates, err := b.listenForWebhook(updater, routes)
+		if err == nil {
+			return updates
+		}
+		b.logger.Error("Failed to start Telegram webhook - falling back to long polling", "error", err)
+	}
+
+	return b.startPolling(updater)
+}
+
+// listenForWebhook registers the bot webhook with Telegram and serves it on the existing router
+func (b *Bot) listenForWebhook(updater Updater, routes RouteRegistrar) (tgbotapi.UpdatesChannel, error) {
+	webhook := b.config.Telegram.Webhook
+	if webhook.SecretToken == "" {
+		return nil, fmt.Errorf("telegram.webhook.secret_token is required in webhook mode")
+	}
+
+	// The secret_token parameter is not exposed by WebhookConfig in this library version
+	params := tgbotapi.Params{}
+	params["url"] = strings.TrimRight(webhook.URL, "/") + webhook.Path
+	params.AddNonEmpty("secret_token", webhook.SecretToken)
+	if _, err := updater.MakeRequest("setWebhook", params); err != nil {
+		return nil, fmt.Errorf("failed to set Telegram webhook: %w", err)
+	}
+
+	updates := make(chan tgbotapi.Update, updatesBuffer)
+	routes.Handle(webhook.Path, b.webhookHandler(webhook.SecretToken, updates))
+
+	// The path is secret, so only the base URL is logged
+	b.logger.Info("🔗 Telegram webhook registered", "base_url", webhook.URL)
+
+	return updates, nil
+}
+
+// webhookHandler verifies and decodes Telegram updates pushed to the webhook path
+// An update that can't be queued (bot stopped, buffer full, caller gone) gets 503 so Telegram sends it again
+func (b *Bot) webhookHandler(secretToken string, updates chan<- tgbotapi.Update) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(telegramSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secretToken)) != 1 {
+			b.logger.Warn("🚫 Telegram webhook call with invalid secret token", "remote_addr", r.RemoteAddr)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update tgbotapi.Update
+		body := http.MaxBytesReader(w, r.Body, b.config.Webhook.MaxRequestSize)
+		if err := json.NewDecoder(body).Decode(&update); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				b.logger.Warn("🚫 Telegram update too large", "limit_bytes", tooLarge.Limit, "remote_addr", r.RemoteAddr)
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			b.logger.Error("Failed to decode Telegram update", "error", err)
+			http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+			return
+		}
+
+		// Run no longer reads the buffer once stopped
+		select {
+		case <-b.stop:
+			http.Error(w, "Bot is shutting down", http.StatusServiceUnavailable)
+			return
+		default:
+		}
+
+		wait := time.NewTimer(updateQueueWait)
+		defer wait.Stop()
+
+		select {
+		case updates <- update:
+			w.WriteHeader(http.StatusOK)
+		case <-b.stop:
+			http.Error(w, "Bot is shutting down", http.StatusServiceUnavailable)
+		case <-wait.C:
+			b.logger.Warn("⚠️ Telegram update buffer full - asking Telegram to retry", "update_id", update.UpdateID)
+			http.Error(w, "Update buffer full", http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+			http.Error(w, "Request cancelled", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// startPolling falls back to long polling, removing a stale webhook that would block getUpdates
+func (b *Bot) startPolling(updater Updater) tgbotapi.UpdatesChannel {
+	info, err := updater.GetWebhookInfo()
+	if err != nil {
+		b.logger.Warn("Failed to fetch Telegram webhook info", "error", err)
+	} else if info.IsSet() {
+		b.logger.Warn("Removing Telegram webhook to use long polling", "url", info.URL)
+		if _, err := updater.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+			b.logger.Error("Failed to remove Telegram webhook", "error", err)
+		}
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	b.stopUpdates = updater.StopReceivingUpdates
+	return updater.GetUpdatesChan(u)
+}
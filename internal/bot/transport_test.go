@@ -0,0 +1,86 @@
+package bot_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"novinhub-webhook/internal/bot/bottest"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const webhookSecret = "test-secret"
+
+// fakeUpdater accepts setWebhook; polling is never used in these tests
+type fakeUpdater struct {
+	*bottest.FakeSender
+}
+
+func (fakeUpdater) MakeRequest(string, tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (fakeUpdater) GetUpdatesChan(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	return make(chan tgbotapi.Update)
+}
+
+func (fakeUpdater) StopReceivingUpdates() {}
+
+func (fakeUpdater) GetWebhookInfo() (tgbotapi.WebhookInfo, error) {
+	return tgbotapi.WebhookInfo{}, nil
+}
+
+// routes captures the webhook handler the bot registers
+type routes map[string]http.Handler
+
+func (r routes) Handle(path string, handler http.Handler) {
+	r[path] = handler
+}
+
+// webhookHarness starts the bot in webhook mode and returns the registered handler
+func webhookHarness(t *testing.T) (*bottest.Harness, http.Handler) {
+	h := newHarness()
+	h.Config.Telegram.Webhook.URL = "https://example.com"
+	h.Config.Telegram.Webhook.Path = "/telegram/test"
+	h.Config.Telegram.Webhook.SecretToken = webhookSecret
+
+	registered := routes{}
+	h.Bot.Updates(fakeUpdater{h.Sender}, registered)
+	handler, ok := registered["/telegram/test"]
+	if !ok {
+		t.Fatal("webhook route not registered")
+	}
+	return h, handler
+}
+
+func postUpdate(handler http.Handler, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/telegram/test", strings.NewReader(body))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", webhookSecret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookRejectsOversizedUpdate(t *testing.T) {
+	h, handler := webhookHarness(t)
+
+	body := `{"update_id": 1, "message": {"text": "` + strings.Repeat("a", int(h.Config.Webhook.MaxRequestSize)) + `"}}`
+	if code := postUpdate(handler, body); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", code)
+	}
+}
+
+func TestWebhookAsksForRetryAfterStop(t *testing.T) {
+	h, handler := webhookHarness(t)
+
+	if code := postUpdate(handler, `{"update_id": 1}`); code != http.StatusOK {
+		t.Fatalf("expected 200 while running, got %d", code)
+	}
+
+	h.Bot.Stop()
+	if code := postUpdate(handler, `{"update_id": 2}`); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after Stop, got %d", code)
+	}
+}
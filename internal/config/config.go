@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,15 +17,39 @@ type Config struct {
 	Security SecurityConfig    `mapstructure:"security"`
 	Health   HealthConfig      `mapstructure:"health"`
 	SMS      SMSConfig         `mapstructure:"sms"`
+	Stats    StatsConfig       `mapstructure:"stats"`
+	Report   ReportConfig      `mapstructure:"report"`
+	Audit    AuditConfig       `mapstructure:"audit"`
+	OptOut   OptOutConfig      `mapstructure:"optout"`
+	Drip     DripConfig        `mapstructure:"drip"`
+	AdminAPI AdminAPIConfig    `mapstructure:"admin_api"`
+	Telegram TelegramConfig    `mapstructure:"telegram"`
 	Env      EnvironmentConfig `mapstructure:"environment"`
+
+	patternMu     sync.Mutex
+	patternChange PatternChange
+
+	reloadMu sync.RWMutex      // Guards settings reloaded at runtime other than patterns
+	settings map[string]string // Flattened settings last applied, for reporting what a reload changed
+
+	secretsInFile []string // Secret settings found in a config file instead of the environment
+	loader        *loader  // Layers the configuration was read from, for reloads and config dumps
+}
+
+// PatternChange records when and by whom the active pattern last changed; fields are zero when not known,
+// e.g. for the pattern loaded at startup
+type PatternChange struct {
+	At time.Time
+	By string
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port         int    `mapstructure:"port"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
-	Host         string `mapstructure:"host"`
+	Port            int    `mapstructure:"port"`
+	ReadTimeout     int    `mapstructure:"read_timeout"`
+	WriteTimeout    int    `mapstructure:"write_timeout"`
+	Host            string `mapstructure:"host"`
+	ShutdownTimeout int    `mapstructure:"shutdown_timeout"` // Seconds allowed to finish requests and the SMS queue on shutdown
 }
 
 // LoggingConfig holds logging-related configuration
@@ -43,9 +69,14 @@ type WebhookConfig struct {
 
 // SecurityConfig holds security-related configuration
 type SecurityConfig struct {
-	EnableCORS     bool     `mapstructure:"enable_cors"`
-	AllowedOrigins []string `mapstructure:"allowed_origins"`
-	RateLimit      int      `mapstructure:"rate_limit"`
+	EnableCORS           bool     `mapstructure:"enable_cors"`
+	AllowedOrigins       []string `mapstructure:"allowed_origins"`        // Exact origins or "https://*.example.com"; empty allows all
+	CORSAllowCredentials bool     `mapstructure:"cors_allow_credentials"` // Requires explicit allowed_origins
+	CORSMaxAge           int      `mapstructure:"cors_max_age"`           // Seconds browsers may cache a preflight response
+	RateLimit            int      `mapstructure:"rate_limit"`             // Requests per minute per IP on /webhook (0 disables)
+	HealthRateLimit      int      `mapstructure:"health_rate_limit"`      // Requests per minute per IP on /health (0 disables)
+	AdminRateLimit       int      `mapstructure:"admin_rate_limit"`       // Requests per minute per IP on admin routes (0 disables)
+	TrustedProxies       []string `mapstructure:"trusted_proxies"`        // IPs or CIDRs whose X-Forwarded-For is believed
 }
 
 // HealthConfig holds health check configuration
@@ -56,11 +87,15 @@ type HealthConfig struct {
 
 // SMSConfig holds SMS-related configuration
 type SMSConfig struct {
-	Provider string        `mapstructure:"provider"`
-	IPPanel  IPPanelConfig `mapstructure:"ippanel"`
-	Enabled  bool          `mapstructure:"enabled"`
-	Retry    RetryConfig   `mapstructure:"retry"`
-	Patterns PatternConfig `mapstructure:"patterns"`
+	Provider   string           `mapstructure:"provider"`
+	IPPanel    IPPanelConfig    `mapstructure:"ippanel"`
+	Enabled    bool             `mapstructure:"enabled"`
+	Retry      RetryConfig      `mapstructure:"retry"`
+	Patterns   PatternConfig    `mapstructure:"patterns"`
+	QueueFile  string           `mapstructure:"queue_file"`  // Where queued SMS jobs are kept across restarts
+	PauseFile  string           `mapstructure:"pause_file"`  // Where a kill switch pause is kept across restarts
+	Limits     []SMSLimitRule   `mapstructure:"limits"`      // Per-phone policy checked before every lead SMS
+	QuietHours QuietHoursConfig `mapstructure:"quiet_hours"` // When lead SMS are deferred instead of sent
 }
 
 // IPPanelConfig holds IPPanel-specific configuration
@@ -69,6 +104,25 @@ type IPPanelConfig struct {
 	Originator string `mapstructure:"originator"`
 }
 
+// SMS limit scopes: which sends count towards a rule
+const (
+	LimitScopePhone        = "phone"         // Every send to the phone
+	LimitScopePhoneAccount = "phone_account" // Sends to the phone for the same NovinHub account (user_id)
+	LimitScopePhonePattern = "phone_pattern" // Sends to the phone with the pattern about to be used
+)
+
+// SMSLimitRule allows at most Max lead SMS per scope within a rolling window
+type SMSLimitRule struct {
+	Scope       string `mapstructure:"scope"`
+	Max         int    `mapstructure:"max"`
+	WindowHours int    `mapstructure:"window_hours"`
+}
+
+// Window returns the rule's rolling window
+func (r SMSLimitRule) Window() time.Duration {
+	return time.Duration(r.WindowHours) * time.Hour
+}
+
 // RetryConfig holds retry-related configuration
 type RetryConfig struct {
 	MaxAttempts  int `mapstructure:"max_attempts"`
@@ -82,35 +136,123 @@ type PatternConfig struct {
 	Current int      `mapstructure:"current"`
 }
 
+// StatsConfig holds statistics store configuration
+type StatsConfig struct {
+	FilePath      string `mapstructure:"file_path"`
+	RetentionDays int    `mapstructure:"retention_days"`
+}
+
+// ReportConfig holds daily report configuration
+type ReportConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Time    string `mapstructure:"time"` // HH:MM in Tehran time
+}
+
+// AuditConfig holds administrative audit log configuration
+type AuditConfig struct {
+	FilePath string `mapstructure:"file_path"` // Append-only JSON lines file; empty keeps entries in memory only
+}
+
+// OptOutConfig holds the do-not-contact list configuration
+type OptOutConfig struct {
+	FilePath string   `mapstructure:"file_path"` // JSON file holding the list; empty keeps it in memory only
+	Keywords []string `mapstructure:"keywords"`  // Words in an inbound message that opt its sender out
+}
+
+// DripConfig holds the follow-up SMS sequences sent after a lead's first SMS
+type DripConfig struct {
+	Enabled   bool           `mapstructure:"enabled"`
+	FilePath  string         `mapstructure:"file_path"` // JSON file holding each lead's progress; empty keeps it in memory only
+	Sequences []DripSequence `mapstructure:"sequences"` // A lead joins the first sequence matching its platform
+}
+
+// DripSequence is a named series of follow-up SMS
+type DripSequence struct {
+	Name      string     `mapstructure:"name"`
+	Platforms []string   `mapstructure:"platforms"` // Lead platforms this sequence is for; empty means every platform
+	Steps     []DripStep `mapstructure:"steps"`
+}
+
+// DripStep sends Pattern DelayHours after the lead arrived
+type DripStep struct {
+	Pattern    string `mapstructure:"pattern"` // Empty sends the active daily pattern
+	DelayHours int    `mapstructure:"delay_hours"`
+}
+
+// Delay returns how long after the lead the step is sent
+func (s DripStep) Delay() time.Duration {
+	return time.Duration(s.DelayHours) * time.Hour
+}
+
+// Matches reports whether a lead from platform joins the sequence
+func (s DripSequence) Matches(platform string) bool {
+	if len(s.Platforms) == 0 {
+		return true
+	}
+	for _, p := range s.Platforms {
+		if strings.EqualFold(p, platform) {
+			return true
+		}
+	}
+	return false
+}
+
+// AdminAPIConfig holds the HTTP admin API configuration
+type AdminAPIConfig struct {
+	Token string `mapstructure:"token"` // Bearer token for /admin routes; empty disables the API
+}
+
+// TelegramConfig holds Telegram bot configuration
+type TelegramConfig struct {
+	Token     string                `mapstructure:"token"`
+	Embedded  bool                  `mapstructure:"embedded"` // Run the bot inside the webhook server
+	Admins    []TelegramAdmin       `mapstructure:"admins"`
+	Webhook   TelegramWebhookConfig `mapstructure:"webhook"`
+	StateFile string                `mapstructure:"state_file"` // Preferences changed from the bot, e.g. admin languages
+
+	UndoWindowMinutes int `mapstructure:"undo_window_minutes"` // How long a pattern change can be undone from the bot (0 disables undo)
+}
+
+// TelegramAdmin holds a Telegram user allowed to use the bot
+type TelegramAdmin struct {
+	ID       int64  `mapstructure:"id"`
+	Name     string `mapstructure:"name"`
+	Owner    bool   `mapstructure:"owner"`    // Owners may perform sensitive actions like pausing SMS
+	Language string `mapstructure:"language"` // Bot language: "fa" (default) or "en"
+}
+
+// TelegramWebhookConfig holds settings for receiving bot updates via webhook instead of long polling
+type TelegramWebhookConfig struct {
+	URL         string `mapstructure:"url"`          // Public base URL, e.g. https://asllmarket.org
+	Path        string `mapstructure:"path"`         // Secret path served by this server
+	SecretToken string `mapstructure:"secret_token"` // Checked against X-Telegram-Bot-Api-Secret-Token
+}
+
+// Enabled returns true if webhook mode is configured
+func (t TelegramWebhookConfig) Enabled() bool {
+	return t.URL != "" && t.Path != ""
+}
+
 // EnvironmentConfig holds environment-specific configuration
 type EnvironmentConfig struct {
 	Mode  string `mapstructure:"mode"`
 	Debug bool   `mapstructure:"debug"`
 }
 
-// Load loads configuration from YAML file and environment variables
+// Load loads configuration from YAML files and environment variables
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./internal/config")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath("/etc/novinhub-webhook")
-	viper.AddConfigPath("$HOME/.novinhub-webhook")
-
-	// Set default values
-	setDefaults()
-
-	// Enable reading from environment variables
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	// Read config file
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("error reading config file: %w", err)
-		}
-		// Config file not found, use defaults and environment variables
+	return LoadWithOptions(LoadOptions{})
+}
+
+// LoadWithOptions loads configuration in layers, each overriding the previous one:
+// defaults, the base config file, the config.<mode>.yaml overlay next to it,
+// environment variables, secret files and command line overrides
+func LoadWithOptions(opts LoadOptions) (*Config, error) {
+	l := &loader{opts: opts}
+	l.configure()
+
+	if err := l.read(); err != nil {
+		return nil, err
 	}
 
 	var config Config
@@ -118,6 +260,10 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	config.settings = currentSettings()
+	config.secretsInFile = secretsInConfigFiles(l.files())
+	config.loader = l
+
 	return &config, nil
 }
 
@@ -126,8 +272,9 @@ func setDefaults() {
 	// Server defaults
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.read_timeout", 10)
-	viper.SetDefault("server.write_timeout", 10)
+	viper.SetDefault("server.write_timeout", 45)
 	viper.SetDefault("server.host", "0.0.0.0")
+	viper.SetDefault("server.shutdown_timeout", 15)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -143,7 +290,12 @@ func setDefaults() {
 	// Security defaults
 	viper.SetDefault("security.enable_cors", true)
 	viper.SetDefault("security.allowed_origins", []string{})
+	viper.SetDefault("security.cors_allow_credentials", false)
+	viper.SetDefault("security.cors_max_age", 600)
 	viper.SetDefault("security.rate_limit", 100)
+	viper.SetDefault("security.health_rate_limit", 60)
+	viper.SetDefault("security.admin_rate_limit", 30)
+	viper.SetDefault("security.trusted_proxies", []string{"127.0.0.1", "::1"})
 
 	// Health defaults
 	viper.SetDefault("health.endpoint", "/health")
@@ -164,6 +316,46 @@ func setDefaults() {
 		"nv4fgs9mczuv6rq", // گروه چهارم
 	})
 	viper.SetDefault("sms.patterns.current", 0)
+	viper.SetDefault("sms.queue_file", "data/sms_queue.json")
+	viper.SetDefault("sms.pause_file", "data/sms_pause.json")
+	viper.SetDefault("sms.quiet_hours.enabled", false)
+	viper.SetDefault("sms.quiet_hours.default", "22:00-08:00")
+	viper.SetDefault("sms.quiet_hours.holiday_hours", QuietAllDay)
+
+	// Stats defaults
+	viper.SetDefault("stats.file_path", "data/stats.jsonl")
+	viper.SetDefault("stats.retention_days", 90)
+
+	// Report defaults
+	viper.SetDefault("report.enabled", true)
+	viper.SetDefault("report.time", "00:00")
+
+	// Audit defaults
+	viper.SetDefault("audit.file_path", "data/audit.jsonl")
+
+	// Opt-out defaults
+	viper.SetDefault("optout.file_path", "data/optout.json")
+	viper.SetDefault("optout.keywords", []string{"لغو", "انصراف", "stop", "unsubscribe"})
+
+	// Drip defaults
+	viper.SetDefault("drip.enabled", false)
+	viper.SetDefault("drip.file_path", "data/drip.json")
+
+	// Admin API defaults
+	viper.SetDefault("admin_api.token", "")
+
+	// Telegram defaults (empty webhook settings fall back to long polling)
+	viper.SetDefault("telegram.token", "")
+	viper.SetDefault("telegram.embedded", true)
+	viper.SetDefault("telegram.admins", []map[string]interface{}{
+		{"id": 76599340, "name": "Admin Original", "owner": true},        // ادمین اصلی
+		{"id": 110435852, "name": "MahYaR (@Saeidpour)", "owner": false}, // ادمین جدید
+	})
+	viper.SetDefault("telegram.webhook.url", "")
+	viper.SetDefault("telegram.webhook.path", "")
+	viper.SetDefault("telegram.webhook.secret_token", "")
+	viper.SetDefault("telegram.state_file", "data/bot_state.json")
+	viper.SetDefault("telegram.undo_window_minutes", 10)
 
 	// Environment defaults
 	viper.SetDefault("environment.mode", "development")
@@ -175,6 +367,11 @@ func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
 }
 
+// ShutdownTimeout returns how long shutdown may take before the process exits anyway
+func (c *Config) ShutdownTimeout() time.Duration {
+	return time.Duration(c.Server.ShutdownTimeout) * time.Second
+}
+
 // IsProduction returns true if running in production mode
 func (c *Config) IsProduction() bool {
 	return c.Env.Mode == "production"
@@ -189,6 +386,9 @@ func (c *Config) IsDevelopment() bool {
 
 // GetCurrentPattern returns the current pattern
 func (c *Config) GetCurrentPattern() string {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
 	if !c.SMS.Patterns.Enabled || len(c.SMS.Patterns.List) == 0 {
 		return "" // No patterns configured
 	}
@@ -203,6 +403,9 @@ func (c *Config) GetCurrentPattern() string {
 
 // GetCurrentPatternInfo returns current pattern with index and group name
 func (c *Config) GetCurrentPatternInfo() (string, int, string) {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
 	if !c.SMS.Patterns.Enabled || len(c.SMS.Patterns.List) == 0 {
 		return "", 0, "هیچ پترنی تنظیم نشده"
 	}
@@ -219,14 +422,18 @@ func (c *Config) GetCurrentPatternInfo() (string, int, string) {
 	return c.SMS.Patterns.List[c.SMS.Patterns.Current], index, groupName
 }
 
-// NextPattern moves to the next pattern
-func (c *Config) NextPattern() (string, int, string) {
+// NextPattern moves to the next pattern on behalf of by
+func (c *Config) NextPattern(by string) (string, int, string) {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
 	if !c.SMS.Patterns.Enabled || len(c.SMS.Patterns.List) == 0 {
 		return "", 0, "هیچ پترنی تنظیم نشده"
 	}
 
 	// Move to next pattern (circular)
 	c.SMS.Patterns.Current = (c.SMS.Patterns.Current + 1) % len(c.SMS.Patterns.List)
+	c.patternChange = PatternChange{At: time.Now(), By: by}
 
 	groupNames := []string{"گروه اول", "گروه دوم", "گروه سوم", "گروه چهارم"}
 	index := c.SMS.Patterns.Current + 1
@@ -235,8 +442,11 @@ func (c *Config) NextPattern() (string, int, string) {
 	return c.SMS.Patterns.List[c.SMS.Patterns.Current], index, groupName
 }
 
-// SetPattern sets a specific pattern by index
-func (c *Config) SetPattern(index int) error {
+// SetPattern sets a specific pattern by index on behalf of by
+func (c *Config) SetPattern(index int, by string) error {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
 	if !c.SMS.Patterns.Enabled || len(c.SMS.Patterns.List) == 0 {
 		return fmt.Errorf("pattern management is disabled")
 	}
@@ -246,11 +456,37 @@ func (c *Config) SetPattern(index int) error {
 	}
 
 	c.SMS.Patterns.Current = index
+	c.patternChange = PatternChange{At: time.Now(), By: by}
 	return nil
 }
 
+// RestorePatternChange records a change saved before a restart, if pattern is still the active one
+// and nothing changed it since startup
+func (c *Config) RestorePatternChange(pattern string, change PatternChange) bool {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
+	if pattern == "" || c.currentPatternLocked() != pattern || c.patternChange != (PatternChange{}) {
+		return false
+	}
+
+	c.patternChange = change
+	return true
+}
+
+// LastPatternChange returns when and by whom the active pattern last changed
+func (c *Config) LastPatternChange() PatternChange {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
+	return c.patternChange
+}
+
 // GetPatternsList returns all patterns with their info
 func (c *Config) GetPatternsList() []map[string]interface{} {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
 	if !c.SMS.Patterns.Enabled || len(c.SMS.Patterns.List) == 0 {
 		return []map[string]interface{}{
 			{
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/viper"
+)
+
+// LoadOptions selects the config file and the overrides given on the command line
+type LoadOptions struct {
+	ConfigPath string            // Base config file or directory; defaults to CONFIG_PATH, then the search paths
+	Mode       string            // Overrides environment.mode, which selects the config.<mode>.yaml overlay
+	Overrides  map[string]string // Settings from --set key=value, applied last
+}
+
+// RegisterFlags adds --config, --mode and --set to fs and returns the options they fill
+func RegisterFlags(fs *flag.FlagSet) *LoadOptions {
+	opts := &LoadOptions{Overrides: make(map[string]string)}
+	fs.StringVar(&opts.ConfigPath, "config", "", "base config file or directory (default $CONFIG_PATH or the search paths)")
+	fs.StringVar(&opts.Mode, "mode", "", "environment mode selecting the config.<mode>.yaml overlay (default environment.mode)")
+	fs.Var(setFlag(opts.Overrides), "set", "override a setting, e.g. --set server.port=9090 (repeatable)")
+	return opts
+}
+
+// setFlag collects repeated key=value flags
+type setFlag map[string]string
+
+func (f setFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for key, value := range f {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f setFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[strings.ToLower(key)] = val
+	return nil
+}
+
+// Configuration layers, lowest precedence first
+const (
+	SourceDefault    = "default"
+	SourceBase       = "base file"
+	SourceOverlay    = "overlay file"
+	SourceEnv        = "env"
+	SourceSecretFile = "secret file"
+	SourceFlag       = "flag"
+)
+
+// loader reads the configuration layers into viper and remembers where they came from
+type loader struct {
+	opts LoadOptions
+
+	basePath    string
+	overlayPath string
+	mode        string
+	modeSource  string // Set when the mode came from --mode or $ENVIRONMENT rather than the settings themselves
+}
+
+// configure sets up viper's search paths, defaults and environment binding
+func (l *loader) configure() {
+	path := l.opts.ConfigPath
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
+
+	viper.SetConfigType("yaml")
+	if info, err := os.Stat(path); path != "" && (err != nil || !info.IsDir()) {
+		// An explicit file must exist; reading it reports the error otherwise
+		viper.SetConfigFile(path)
+	} else {
+		viper.SetConfigName("config")
+		if path != "" {
+			viper.AddConfigPath(path)
+		}
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./internal/config")
+		viper.AddConfigPath("./config")
+		viper.AddConfigPath("/etc/novinhub-webhook")
+		viper.AddConfigPath("$HOME/.novinhub-webhook")
+	}
+
+	// Set default values
+	setDefaults()
+
+	// Enable reading from environment variables
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+}
+
+// read (re)reads the base file, merges the overlay for the mode and applies secret files and flags
+func (l *loader) read() error {
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return fmt.Errorf("error reading config file: %w", err)
+		}
+		// Config file not found, use defaults and environment variables
+	}
+	l.basePath = viper.ConfigFileUsed()
+
+	l.resolveMode()
+	if l.modeSource != "" {
+		viper.Set("environment.mode", l.mode)
+	}
+
+	l.overlayPath = ""
+	if overlay := l.overlayCandidate(); overlay != "" {
+		if err := mergeOverlay(overlay); err != nil {
+			return err
+		}
+		if _, err := os.Stat(overlay); err == nil {
+			l.overlayPath = overlay
+		}
+	}
+
+	// Secrets from files (e.g. Docker or systemd credentials) override the config files and environment
+	if err := loadSecretFiles(); err != nil {
+		return err
+	}
+
+	for key, value := range l.opts.Overrides {
+		viper.Set(key, value)
+	}
+
+	return nil
+}
+
+// resolveMode picks the environment mode: --mode, then ENVIRONMENT_MODE, then ENVIRONMENT, then the base file
+func (l *loader) resolveMode() {
+	l.modeSource = ""
+
+	switch {
+	case l.opts.Mode != "":
+		l.mode, l.modeSource = l.opts.Mode, SourceFlag
+	case os.Getenv("ENVIRONMENT_MODE") != "":
+		l.mode = os.Getenv("ENVIRONMENT_MODE")
+	case os.Getenv("ENVIRONMENT") != "":
+		l.mode, l.modeSource = os.Getenv("ENVIRONMENT"), SourceEnv+" ENVIRONMENT"
+	default:
+		l.mode = viper.GetString("environment.mode")
+	}
+}
+
+// overlayCandidate returns the overlay path for the current mode, whether or not it exists
+func (l *loader) overlayCandidate() string {
+	if l.basePath == "" || l.mode == "" {
+		return ""
+	}
+
+	overlay := filepath.Join(filepath.Dir(l.basePath), "config."+l.mode+".yaml")
+	if filepath.Clean(overlay) == filepath.Clean(l.basePath) {
+		return ""
+	}
+	return overlay
+}
+
+// mergeOverlay merges an overlay file into viper; a missing overlay is not an error
+func mergeOverlay(path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening config overlay: %w", err)
+	}
+	defer file.Close()
+
+	if err := viper.MergeConfig(file); err != nil {
+		return fmt.Errorf("error reading config overlay %s: %w", path, err)
+	}
+	return nil
+}
+
+// files returns the config files that were read, base first
+func (l *loader) files() []string {
+	var files []string
+	for _, path := range []string{l.basePath, l.overlayPath} {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// source returns the layer that supplied a setting's effective value
+func (l *loader) source(key string, base, overlay *viper.Viper) string {
+	if _, ok := l.opts.Overrides[key]; ok {
+		return SourceFlag
+	}
+	if key == "environment.mode" && l.modeSource != "" {
+		return l.modeSource
+	}
+	if IsSecretKey(key) && os.Getenv(envName(key)+"_FILE") != "" {
+		return SourceSecretFile + " " + envName(key) + "_FILE"
+	}
+	if os.Getenv(envName(key)) != "" {
+		return SourceEnv + " " + envName(key)
+	}
+	if overlay != nil && overlay.IsSet(key) {
+		return SourceOverlay + " " + l.overlayPath
+	}
+	if base != nil && base.IsSet(key) {
+		return SourceBase + " " + l.basePath
+	}
+	return SourceDefault
+}
+
+// readFile loads a single config file into its own viper instance
+func readFile(path string) *viper.Viper {
+	if path == "" {
+		return nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil
+	}
+	return v
+}
+
+// Setting is one effective configuration value and the layer it came from
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source string
+}
+
+// EffectiveSettings returns every setting, with secrets redacted, and the layer that supplied it
+func (c *Config) EffectiveSettings() []Setting {
+	l := c.loader
+	if l == nil {
+		l = &loader{}
+	}
+	base, overlay := readFile(l.basePath), readFile(l.overlayPath)
+
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+
+	settings := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		value := viper.Get(key)
+		if IsSecretKey(key) && fmt.Sprint(value) != "" {
+			value = redacted
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: l.source(key, base, overlay)})
+	}
+	return settings
+}
+
+// PrintEffective writes the merged configuration and the source of each value
+func (c *Config) PrintEffective(w io.Writer) {
+	if c.loader != nil {
+		fmt.Fprintf(w, "# mode: %s\n", c.Env.Mode)
+		for _, file := range c.loader.files() {
+			fmt.Fprintf(w, "# file: %s\n", file)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, setting := range c.EffectiveSettings() {
+		fmt.Fprintf(tw, "%s\t= %v  # %s\n", setting.Key, setting.Value, setting.Source)
+	}
+	tw.Flush()
+}
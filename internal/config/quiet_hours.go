@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"novinhub-webhook/internal/utils"
+)
+
+// Special sms.quiet_hours values
+const (
+	QuietAllDay = "all"  // No SMS at any time of the day
+	QuietNone   = "none" // No quiet hours that day
+)
+
+// maxQuietDays bounds how far ahead QuietUntil follows back-to-back quiet periods (e.g. a holiday run)
+const maxQuietDays = 16
+
+// weekdays maps the sms.quiet_hours.days keys to weekdays
+var weekdays = map[string]time.Weekday{
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+}
+
+// QuietHoursConfig defers lead SMS arriving at night or on holidays to the next allowed time (Tehran time)
+type QuietHoursConfig struct {
+	Enabled      bool              `mapstructure:"enabled"`
+	Default      string            `mapstructure:"default"`       // Quiet ranges for days not listed in Days, e.g. "22:00-08:00"
+	Days         map[string]string `mapstructure:"days"`          // Per weekday ("saturday" … "friday"): ranges, "all" or "none"
+	Holidays     []string          `mapstructure:"holidays"`      // Dates, Jalali ("1405-01-01") or Gregorian ("2026-03-21")
+	HolidayHours string            `mapstructure:"holiday_hours"` // Quiet ranges added on holidays
+}
+
+// QuietRange is a quiet span in minutes after midnight; an End before Start runs past midnight into the next day
+type QuietRange struct {
+	Start int
+	End   int
+}
+
+// ParseQuietRanges parses comma-separated "HH:MM-HH:MM" ranges, "all" or "none"
+func ParseQuietRanges(value string) ([]QuietRange, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case QuietAllDay:
+		return []QuietRange{{Start: 0, End: 24 * 60}}, nil
+	case QuietNone, "":
+		return nil, nil
+	}
+
+	var ranges []QuietRange
+	for _, part := range strings.Split(value, ",") {
+		r, err := parseQuietRange(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseQuietRange parses a single "HH:MM-HH:MM" range
+func parseQuietRange(value string) (QuietRange, error) {
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		return QuietRange{}, fmt.Errorf("quiet range must be HH:MM-HH:MM, %q or %q, got %q", QuietAllDay, QuietNone, value)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietRange{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietRange{}, err
+	}
+	if start == end {
+		return QuietRange{}, fmt.Errorf("quiet range %q is empty - use %q or %q", value, QuietAllDay, QuietNone)
+	}
+
+	return QuietRange{Start: start, End: end}, nil
+}
+
+// parseClock parses HH:MM into minutes after midnight; "24:00" is allowed as an end of day
+func parseClock(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("time must be HH:MM, got %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ParseHolidayDate normalizes a holiday to YYYY-MM-DD; years before 1700 are Jalali
+func ParseHolidayDate(value string) (string, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(value), func(r rune) bool { return r == '-' || r == '/' })
+	if len(parts) != 3 {
+		return "", fmt.Errorf("holiday must be YYYY-MM-DD, got %q", value)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", fmt.Errorf("holiday must be YYYY-MM-DD, got %q", value)
+		}
+		numbers[i] = n
+	}
+
+	year, month, day := numbers[0], numbers[1], numbers[2]
+	maxDay := 31
+	if year < 1700 && month > 6 {
+		maxDay = 30
+	}
+	if month < 1 || month > 12 || day < 1 || day > maxDay {
+		return "", fmt.Errorf("holiday %q is not a valid date", value)
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day), nil
+}
+
+// IsHoliday reports whether t falls on one of the configured holidays in Tehran
+func (q QuietHoursConfig) IsHoliday(t time.Time) bool {
+	if len(q.Holidays) == 0 {
+		return false
+	}
+
+	t = t.In(utils.TehranLocation())
+	jy, jm, jd := utils.ToJalali(t)
+	gregorian := t.Format("2006-01-02")
+	jalali := fmt.Sprintf("%04d-%02d-%02d", jy, jm, jd)
+
+	for _, holiday := range q.Holidays {
+		date, err := ParseHolidayDate(holiday)
+		if err == nil && (date == gregorian || date == jalali) {
+			return true
+		}
+	}
+	return false
+}
+
+// rangesFor returns the quiet ranges of the Tehran day starting at midnight day; invalid values count as no quiet hours
+// Holiday hours are added to the weekday's ranges, so the night after a holiday stays quiet
+func (q QuietHoursConfig) rangesFor(day time.Time) []QuietRange {
+	value := q.Default
+	if dayValue, ok := q.Days[strings.ToLower(day.Weekday().String())]; ok {
+		value = dayValue
+	}
+	ranges, _ := ParseQuietRanges(value)
+
+	if q.IsHoliday(day) {
+		holiday, _ := ParseQuietRanges(q.HolidayHours)
+		ranges = append(ranges, holiday...)
+	}
+	return ranges
+}
+
+// quietEnd returns when the quiet period covering t ends; ranges running past midnight start on the day before
+func (q QuietHoursConfig) quietEnd(t time.Time) (time.Time, bool) {
+	today := utils.StartOfDay(t)
+	var end time.Time
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		for _, r := range q.rangesFor(day) {
+			loc := day.Location()
+			from := time.Date(day.Year(), day.Month(), day.Day(), 0, r.Start, 0, 0, loc)
+			to := time.Date(day.Year(), day.Month(), day.Day(), 0, r.End, 0, 0, loc)
+			if r.End <= r.Start {
+				to = to.AddDate(0, 0, 1)
+			}
+
+			if !t.Before(from) && t.Before(to) && to.After(end) {
+				end = to
+			}
+		}
+	}
+	return end, !end.IsZero()
+}
+
+// QuietUntil returns when SMS may be sent again if t falls in quiet hours, following back-to-back quiet periods
+func (q QuietHoursConfig) QuietUntil(t time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+
+	until := t.In(utils.TehranLocation())
+	for i := 0; i < maxQuietDays*2; i++ {
+		end, quiet := q.quietEnd(until)
+		if !quiet {
+			break
+		}
+		until = end
+	}
+
+	if !until.After(t) {
+		return time.Time{}, false
+	}
+	return until, true
+}
@@ -0,0 +1,378 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadableKeys are the settings applied to the running service when the config file changes;
+// keys ending in "." cover a whole section
+var reloadableKeys = []string{
+	"sms.patterns.",
+	"sms.enabled",
+	"sms.limits",
+	"sms.quiet_hours.",
+	"drip.enabled",
+	"drip.sequences",
+	"logging.level",
+	"security.rate_limit",
+	"security.health_rate_limit",
+	"security.admin_rate_limit",
+	"telegram.admins",
+}
+
+// ReloadActor is recorded as the author of changes made by editing the config file
+const ReloadActor = "config reload"
+
+// ReloadResult describes what a config file change did
+type ReloadResult struct {
+	Applied         []string         // Changed keys now in effect
+	RestartRequired []string         // Changed keys that only take effect after a restart
+	Changes         []SettingChange  // Audited settings whose effective value changed
+	Validation      ValidationResult // Problems in the new file; nothing is applied when it has errors
+}
+
+// SettingChange is the effective value of telegram.admins, sms.patterns.current or sms.enabled before and after a reload
+type SettingChange struct {
+	Key    string
+	Before string
+	After  string
+}
+
+// Rejected returns true if the new configuration was invalid and nothing was applied
+func (r ReloadResult) Rejected() bool {
+	return !r.Validation.OK()
+}
+
+// isReloadable returns true if a changed key can be applied without a restart
+func isReloadable(key string) bool {
+	for _, reloadable := range reloadableKeys {
+		if key == reloadable || (strings.HasSuffix(reloadable, ".") && strings.HasPrefix(key, reloadable)) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentSettings flattens viper's effective settings for comparison between reloads
+func currentSettings() map[string]string {
+	settings := make(map[string]string)
+	for _, key := range viper.AllKeys() {
+		settings[key] = fmt.Sprint(viper.Get(key))
+	}
+	return settings
+}
+
+// Watch re-reads the config files whenever the base file or its overlay changes and applies the reloadable settings
+// Returns false if no config file was loaded, so there is nothing to watch
+func (c *Config) Watch(onReload func(ReloadResult)) bool {
+	if c.loader == nil || c.loader.basePath == "" {
+		return false
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return false
+	}
+
+	// Watch the directories so files replaced by editors or config management are still seen,
+	// and an overlay created after startup is picked up
+	watched := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, path := range []string{c.loader.basePath, c.loader.overlayPath, c.loader.overlayCandidate()} {
+		if path == "" {
+			continue
+		}
+		watched[filepath.Clean(path)] = true
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return false
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !watched[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+					continue
+				}
+				if _, err := os.Stat(c.loader.basePath); err != nil {
+					// The base file is being replaced; its Create event follows
+					continue
+				}
+				onReload(c.rereadAndReload())
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+	return true
+}
+
+// rereadAndReload reads every layer again and applies the result
+func (c *Config) rereadAndReload() ReloadResult {
+	if err := c.loader.read(); err != nil {
+		var result ReloadResult
+		result.Validation.errorf("%v", err)
+		return result
+	}
+	return c.Reload()
+}
+
+// Reload validates the configuration viper currently holds and, if it is valid, applies the reloadable settings
+func (c *Config) Reload() ReloadResult {
+	var result ReloadResult
+
+	var next Config
+	if err := viper.Unmarshal(&next); err != nil {
+		result.Validation.errorf("error unmarshaling config: %v", err)
+		return result
+	}
+
+	if c.loader != nil {
+		next.secretsInFile = secretsInConfigFiles(c.loader.files())
+	}
+	result.Validation = next.Validate()
+	if !result.Validation.OK() {
+		return result
+	}
+
+	settings := currentSettings()
+
+	c.reloadMu.Lock()
+	if c.settings == nil {
+		c.settings = make(map[string]string)
+	}
+	var changed []string
+	for key, value := range settings {
+		if old, ok := c.settings[key]; !ok || old != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range c.settings {
+		if _, ok := settings[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	previousEnabled := c.SMS.Enabled
+	previousAdmins := formatAdmins(c.Telegram.Admins)
+
+	currentChanged := false
+	for _, key := range changed {
+		if !isReloadable(key) {
+			// Keep the old value so the key is reported until the service restarts
+			result.RestartRequired = append(result.RestartRequired, key)
+			continue
+		}
+
+		result.Applied = append(result.Applied, key)
+		if value, ok := settings[key]; ok {
+			c.settings[key] = value
+		} else {
+			delete(c.settings, key)
+		}
+		if key == "sms.patterns.current" {
+			currentChanged = true
+		}
+	}
+
+	c.SMS.Enabled = next.SMS.Enabled
+	c.SMS.Limits = next.SMS.Limits
+	c.SMS.QuietHours = next.SMS.QuietHours
+	c.Drip.Enabled = next.Drip.Enabled
+	c.Drip.Sequences = next.Drip.Sequences
+	c.Logging.Level = next.Logging.Level
+	c.Security.RateLimit = next.Security.RateLimit
+	c.Security.HealthRateLimit = next.Security.HealthRateLimit
+	c.Security.AdminRateLimit = next.Security.AdminRateLimit
+	c.Telegram.Admins = next.Telegram.Admins
+	c.reloadMu.Unlock()
+
+	if admins := formatAdmins(next.Telegram.Admins); admins != previousAdmins {
+		result.Changes = append(result.Changes, SettingChange{Key: "telegram.admins", Before: previousAdmins, After: admins})
+	}
+	if previous, current := c.reloadPatterns(next.SMS.Patterns, currentChanged); current != previous {
+		result.Changes = append(result.Changes, SettingChange{Key: "sms.patterns.current", Before: previous, After: current})
+	}
+	if next.SMS.Enabled != previousEnabled {
+		result.Changes = append(result.Changes, SettingChange{
+			Key:    "sms.enabled",
+			Before: fmt.Sprint(previousEnabled),
+			After:  fmt.Sprint(next.SMS.Enabled),
+		})
+	}
+
+	return result
+}
+
+// formatAdmins renders an admin list for the audit log, e.g. "Ali (123, owner), Sara (456)"
+func formatAdmins(admins []TelegramAdmin) string {
+	names := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		name := fmt.Sprintf("%s (%d", admin.Name, admin.ID)
+		if admin.Owner {
+			name += ", owner"
+		}
+		names = append(names, name+")")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// reloadPatterns swaps in the pattern list from the config file
+// The active pattern only follows the file when sms.patterns.current itself changed,
+// so a switch made from the bot survives unrelated edits. Returns the active pattern before and after.
+func (c *Config) reloadPatterns(patterns PatternConfig, currentChanged bool) (string, string) {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
+	previous := c.currentPatternLocked()
+
+	c.SMS.Patterns.Enabled = patterns.Enabled
+	c.SMS.Patterns.List = patterns.List
+	if currentChanged || c.SMS.Patterns.Current >= len(patterns.List) {
+		c.SMS.Patterns.Current = patterns.Current
+	}
+
+	current := c.currentPatternLocked()
+	if current != previous {
+		c.patternChange = PatternChange{At: time.Now(), By: ReloadActor}
+	}
+	return previous, current
+}
+
+// currentPatternLocked returns the active pattern code; callers hold patternMu
+func (c *Config) currentPatternLocked() string {
+	if !c.SMS.Patterns.Enabled || c.SMS.Patterns.Current < 0 || c.SMS.Patterns.Current >= len(c.SMS.Patterns.List) {
+		return ""
+	}
+	return c.SMS.Patterns.List[c.SMS.Patterns.Current]
+}
+
+// SMSEnabled returns whether SMS sending is enabled
+func (c *Config) SMSEnabled() bool {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.SMS.Enabled
+}
+
+// SMSLimits returns the per-phone SMS limit rules
+func (c *Config) SMSLimits() []SMSLimitRule {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return append([]SMSLimitRule(nil), c.SMS.Limits...)
+}
+
+// QuietHours returns the times lead SMS are deferred
+func (c *Config) QuietHours() QuietHoursConfig {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.SMS.QuietHours
+}
+
+// DripEnabled reports whether follow-up sequences are enrolled and sent
+func (c *Config) DripEnabled() bool {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.Drip.Enabled
+}
+
+// DripSequence returns the follow-up sequence a lead from platform joins, if drip is enabled and one matches
+func (c *Config) DripSequence(platform string) (DripSequence, bool) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	if !c.Drip.Enabled {
+		return DripSequence{}, false
+	}
+	for _, sequence := range c.Drip.Sequences {
+		if sequence.Matches(platform) {
+			return sequence, true
+		}
+	}
+	return DripSequence{}, false
+}
+
+// LogLevel returns the configured log level
+func (c *Config) LogLevel() string {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.Logging.Level
+}
+
+// RateLimit returns the configured request rate limit
+func (c *Config) RateLimit() int {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.Security.RateLimit
+}
+
+// HealthRateLimit returns the per-IP requests per minute allowed on the health check
+func (c *Config) HealthRateLimit() int {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.Security.HealthRateLimit
+}
+
+// AdminRateLimit returns the per-IP requests per minute allowed on admin routes
+func (c *Config) AdminRateLimit() int {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return c.Security.AdminRateLimit
+}
+
+// Admins returns the Telegram admins from the config file
+func (c *Config) Admins() []TelegramAdmin {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+
+	return append([]TelegramAdmin(nil), c.Telegram.Admins...)
+}
+
+// PatternCount returns the number of configured patterns
+func (c *Config) PatternCount() int {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
+	return len(c.SMS.Patterns.List)
+}
+
+// PatternAt returns the pattern code at a 0-based index
+func (c *Config) PatternAt(index int) (string, bool) {
+	c.patternMu.Lock()
+	defer c.patternMu.Unlock()
+
+	if index < 0 || index >= len(c.SMS.Patterns.List) {
+		return "", false
+	}
+	return c.SMS.Patterns.List[index], true
+}
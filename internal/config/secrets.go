@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// secretKeys are settings that should come from the environment or a file, never a committed config file
+var secretKeys = []string{
+	"sms.ippanel.api_key",
+	"telegram.token",
+	"telegram.webhook.secret_token",
+	"admin_api.token",
+}
+
+// redacted replaces secret values in config dumps and logs
+const redacted = "[REDACTED]"
+
+// IsSecretKey returns true if a setting holds a secret
+func IsSecretKey(key string) bool {
+	for _, secret := range secretKeys {
+		if key == secret {
+			return true
+		}
+	}
+	return false
+}
+
+// envName returns the environment variable for a setting, e.g. SMS_IPPANEL_API_KEY
+func envName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// loadSecretFiles reads secrets from files named by <VAR>_FILE environment variables,
+// e.g. SMS_IPPANEL_API_KEY_FILE=/run/secrets/ippanel_api_key; these take precedence over everything else
+func loadSecretFiles() error {
+	for _, key := range secretKeys {
+		variable := envName(key) + "_FILE"
+		path := os.Getenv(variable)
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", variable, err)
+		}
+		viper.Set(key, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// secretsInConfigFiles returns the secret settings that have a value in any of the given config files
+func secretsInConfigFiles(paths []string) []string {
+	var found []string
+	for _, path := range paths {
+		file := readFile(path)
+		if file == nil {
+			continue
+		}
+
+		for _, key := range secretKeys {
+			if file.GetString(key) != "" {
+				found = append(found, key+" in "+path)
+			}
+		}
+	}
+	return found
+}
+
+// Secrets returns the non-empty secret values, for redacting them from logs
+func (c *Config) Secrets() []string {
+	var secrets []string
+	for _, value := range []string{c.SMS.IPPanel.APIKey, c.Telegram.Token, c.Telegram.Webhook.SecretToken, c.AdminAPI.Token} {
+		if value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	return secrets
+}
+
+// SecretsInFile returns the secret settings found in the config file at load time
+func (c *Config) SecretsInFile() []string {
+	return c.secretsInFile
+}
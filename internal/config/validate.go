@@ -0,0 +1,430 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"novinhub-webhook/internal/utils"
+)
+
+var (
+	// patternCodePattern matches IPPanel pattern codes such as "a2xjmxbszf27a7e"
+	patternCodePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+	// secretTokenPattern matches the characters Telegram allows in a webhook secret token
+	secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+)
+
+// maxPatterns is the number of pattern groups with names in the bot and reports
+const maxPatterns = 4
+
+// ValidationResult lists the problems found in a configuration
+// Errors make the service misbehave; warnings are suspicious but workable
+type ValidationResult struct {
+	Errors   []string
+	Warnings []string
+}
+
+// OK returns true if no errors were found
+func (r ValidationResult) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// Err returns the errors joined into one, or nil
+func (r ValidationResult) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return errors.New("invalid configuration:\n  - " + strings.Join(r.Errors, "\n  - "))
+}
+
+// String renders the result for the --check-config report
+func (r ValidationResult) String() string {
+	var b strings.Builder
+	for _, e := range r.Errors {
+		b.WriteString("ERROR   " + e + "\n")
+	}
+	for _, w := range r.Warnings {
+		b.WriteString("WARNING " + w + "\n")
+	}
+	if r.OK() {
+		b.WriteString(fmt.Sprintf("Configuration OK (%d warnings)\n", len(r.Warnings)))
+	} else {
+		b.WriteString(fmt.Sprintf("Configuration invalid: %d errors, %d warnings\n", len(r.Errors), len(r.Warnings)))
+	}
+	return b.String()
+}
+
+func (r *ValidationResult) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *ValidationResult) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the configuration and returns every problem found
+func (c *Config) Validate() ValidationResult {
+	var r ValidationResult
+
+	c.validateServer(&r)
+	c.validateSMS(&r)
+	c.validateStorage(&r)
+	c.validateTelegram(&r)
+
+	for _, found := range c.secretsInFile {
+		key, _, _ := strings.Cut(found, " ")
+		r.warnf("SECRET IN CONFIG FILE: %s - set it with the %s environment variable or a file named by %s_FILE instead", found, envName(key), envName(key))
+	}
+
+	switch c.Env.Mode {
+	case "development", "staging", "production":
+	default:
+		r.warnf("environment.mode %q is not one of development, staging, production", c.Env.Mode)
+	}
+
+	return r
+}
+
+func (c *Config) validateServer(r *ValidationResult) {
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		r.errorf("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.ReadTimeout <= 0 {
+		r.errorf("server.read_timeout must be positive, got %d", c.Server.ReadTimeout)
+	}
+	if c.Server.WriteTimeout <= 0 {
+		r.errorf("server.write_timeout must be positive, got %d", c.Server.WriteTimeout)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		r.errorf("server.shutdown_timeout must be positive, got %d", c.Server.ShutdownTimeout)
+	}
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		r.errorf("logging.level must be one of debug, info, warn, error, got %q", c.Logging.Level)
+	}
+	switch c.Logging.Format {
+	case "json", "text":
+	default:
+		r.warnf("logging.format %q is not json or text", c.Logging.Format)
+	}
+
+	if c.Webhook.MaxRequestSize <= 0 {
+		r.errorf("webhook.max_request_size must be positive, got %d", c.Webhook.MaxRequestSize)
+	}
+	if c.Webhook.ProcessingTimeout <= 0 {
+		r.errorf("webhook.processing_timeout must be positive, got %d", c.Webhook.ProcessingTimeout)
+	}
+	// A send still running when the response deadline passes means NovinHub never sees the 200 and retries
+	if c.Webhook.ProcessingTimeout > 0 && c.Server.WriteTimeout > 0 && c.Webhook.ProcessingTimeout >= c.Server.WriteTimeout {
+		r.errorf("webhook.processing_timeout (%ds) must be less than server.write_timeout (%ds), or NovinHub retries slow webhooks and leads get duplicate SMS",
+			c.Webhook.ProcessingTimeout, c.Server.WriteTimeout)
+	}
+	if c.Security.RateLimit < 0 {
+		r.errorf("security.rate_limit must not be negative, got %d", c.Security.RateLimit)
+	}
+	if c.Security.HealthRateLimit < 0 {
+		r.errorf("security.health_rate_limit must not be negative, got %d", c.Security.HealthRateLimit)
+	}
+	if c.Security.AdminRateLimit < 0 {
+		r.errorf("security.admin_rate_limit must not be negative, got %d", c.Security.AdminRateLimit)
+	}
+	if c.Security.EnableCORS {
+		allowAll := len(c.Security.AllowedOrigins) == 0
+		for _, origin := range c.Security.AllowedOrigins {
+			if origin == "*" {
+				allowAll = true
+				continue
+			}
+			if !validOrigin(origin) {
+				r.errorf("security.allowed_origins: %q is not an origin like https://example.com or https://*.example.com", origin)
+			}
+		}
+		if c.Security.CORSAllowCredentials && allowAll {
+			r.errorf("security.cors_allow_credentials needs explicit security.allowed_origins, not all origins")
+		}
+		if c.Security.CORSMaxAge < 0 {
+			r.errorf("security.cors_max_age must not be negative, got %d", c.Security.CORSMaxAge)
+		}
+	}
+	if c.AdminAPI.Token != "" && len(c.AdminAPI.Token) < 16 {
+		r.warnf("admin_api.token is shorter than 16 characters - use a long random token")
+	}
+	for _, proxy := range c.Security.TrustedProxies {
+		if _, err := utils.ParseIPNet(proxy); err != nil {
+			r.errorf("security.trusted_proxies: %v", err)
+		}
+	}
+}
+
+// validOrigin checks a scheme://host[:port] origin whose host may start with "*."
+func validOrigin(origin string) bool {
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return u.Path == "" && u.RawQuery == "" && u.User == nil && !strings.Contains(u.Host, "*")
+}
+
+func (c *Config) validateSMS(r *ValidationResult) {
+	if c.SMS.Provider != "ippanel" {
+		r.errorf("sms.provider %q is not supported (only ippanel)", c.SMS.Provider)
+	}
+
+	if c.SMS.Enabled {
+		if c.SMS.IPPanel.APIKey == "" {
+			r.errorf("sms.ippanel.api_key is required when sms.enabled is true")
+		}
+		if c.SMS.IPPanel.Originator == "" {
+			r.errorf("sms.ippanel.originator is required when sms.enabled is true")
+		}
+	} else {
+		r.warnf("sms.enabled is false - leads will not receive SMS")
+	}
+
+	if c.SMS.Retry.MaxAttempts < 1 {
+		r.warnf("sms.retry.max_attempts is %d - failed sends will not be retried", c.SMS.Retry.MaxAttempts)
+	}
+	if c.SMS.Retry.DelaySeconds < 0 {
+		r.errorf("sms.retry.delay_seconds must not be negative, got %d", c.SMS.Retry.DelaySeconds)
+	}
+
+	retention := time.Duration(c.Stats.RetentionDays) * 24 * time.Hour
+	for i, rule := range c.SMS.Limits {
+		switch rule.Scope {
+		case LimitScopePhone, LimitScopePhoneAccount, LimitScopePhonePattern:
+		default:
+			r.errorf("sms.limits[%d].scope must be one of %s, %s, %s, got %q", i, LimitScopePhone, LimitScopePhoneAccount, LimitScopePhonePattern, rule.Scope)
+		}
+		if rule.Max < 1 {
+			r.errorf("sms.limits[%d].max must be at least 1, got %d", i, rule.Max)
+		}
+		if rule.WindowHours < 1 {
+			r.errorf("sms.limits[%d].window_hours must be at least 1, got %d", i, rule.WindowHours)
+		}
+		if retention > 0 && rule.Window() > retention {
+			r.warnf("sms.limits[%d] window of %d hours is longer than stats.retention_days - older sends are not counted", i, rule.WindowHours)
+		}
+	}
+
+	c.validateQuietHours(r)
+	c.validateDrip(r)
+
+	patterns := c.SMS.Patterns
+	if !patterns.Enabled {
+		if c.SMS.Enabled {
+			r.errorf("sms.patterns.enabled is false - no pattern is available to send")
+		}
+		return
+	}
+
+	if len(patterns.List) == 0 {
+		r.errorf("sms.patterns.list is empty")
+		return
+	}
+	if len(patterns.List) > maxPatterns {
+		r.errorf("sms.patterns.list has %d patterns, at most %d are supported", len(patterns.List), maxPatterns)
+	}
+
+	seen := make(map[string]bool)
+	for i, code := range patterns.List {
+		switch {
+		case code == "":
+			r.errorf("sms.patterns.list[%d] is empty", i)
+		case !patternCodePattern.MatchString(code):
+			r.errorf("sms.patterns.list[%d] %q is not a valid pattern code (lowercase letters and digits only)", i, code)
+		case seen[code]:
+			r.warnf("sms.patterns.list[%d] %q is listed more than once", i, code)
+		}
+		seen[code] = true
+	}
+
+	if patterns.Current < 0 || patterns.Current >= len(patterns.List) {
+		r.errorf("sms.patterns.current must be between 0 and %d, got %d", len(patterns.List)-1, patterns.Current)
+	}
+}
+
+// validateDrip checks the follow-up sequences
+func (c *Config) validateDrip(r *ValidationResult) {
+	if !c.Drip.Enabled {
+		return
+	}
+	if len(c.Drip.Sequences) == 0 {
+		r.warnf("drip.enabled is true but no drip.sequences are configured")
+	}
+
+	names := make(map[string]bool)
+	for i, sequence := range c.Drip.Sequences {
+		if sequence.Name == "" {
+			r.errorf("drip.sequences[%d].name is required", i)
+		} else if names[sequence.Name] {
+			r.errorf("drip.sequences[%d].name %q is used twice", i, sequence.Name)
+		}
+		names[sequence.Name] = true
+
+		if len(sequence.Steps) == 0 {
+			r.errorf("drip.sequences[%d] (%s) has no steps", i, sequence.Name)
+		}
+		previous := 0
+		for j, step := range sequence.Steps {
+			if step.Pattern != "" && !patternCodePattern.MatchString(step.Pattern) {
+				r.errorf("drip.sequences[%d].steps[%d].pattern %q is not a valid pattern code", i, j, step.Pattern)
+			}
+			if step.DelayHours < 1 {
+				r.errorf("drip.sequences[%d].steps[%d].delay_hours must be at least 1 - the first SMS is the lead SMS, got %d", i, j, step.DelayHours)
+			} else if step.DelayHours <= previous {
+				r.errorf("drip.sequences[%d].steps[%d].delay_hours must be later than the step before, got %d", i, j, step.DelayHours)
+			}
+			previous = step.DelayHours
+		}
+
+		if len(sequence.Platforms) == 0 && i < len(c.Drip.Sequences)-1 {
+			r.warnf("drip.sequences[%d] (%s) matches every platform - the sequences after it are never used", i, sequence.Name)
+		}
+	}
+}
+
+// validateQuietHours checks the sms.quiet_hours ranges and holiday dates
+func (c *Config) validateQuietHours(r *ValidationResult) {
+	quiet := c.SMS.QuietHours
+	if !quiet.Enabled {
+		return
+	}
+
+	if _, err := ParseQuietRanges(quiet.Default); err != nil {
+		r.errorf("sms.quiet_hours.default: %v", err)
+	}
+	if _, err := ParseQuietRanges(quiet.HolidayHours); err != nil {
+		r.errorf("sms.quiet_hours.holiday_hours: %v", err)
+	}
+
+	allDay := 0
+	for day := range weekdays {
+		value, ok := quiet.Days[day]
+		if !ok {
+			value = quiet.Default
+		}
+		if strings.EqualFold(strings.TrimSpace(value), QuietAllDay) {
+			allDay++
+		}
+	}
+	if allDay == len(weekdays) {
+		r.errorf("sms.quiet_hours leaves no day to send SMS - every weekday is %q", QuietAllDay)
+	}
+
+	for day, value := range quiet.Days {
+		if _, ok := weekdays[day]; !ok {
+			r.errorf("sms.quiet_hours.days: unknown weekday %q (use saturday … friday)", day)
+			continue
+		}
+		if _, err := ParseQuietRanges(value); err != nil {
+			r.errorf("sms.quiet_hours.days.%s: %v", day, err)
+		}
+	}
+
+	for i, holiday := range quiet.Holidays {
+		if _, err := ParseHolidayDate(holiday); err != nil {
+			r.errorf("sms.quiet_hours.holidays[%d]: %v", i, err)
+		}
+	}
+}
+
+func (c *Config) validateStorage(r *ValidationResult) {
+	if c.Stats.FilePath == "" {
+		r.warnf("stats.file_path is empty - statistics will be lost on restart")
+	}
+	if c.Stats.RetentionDays < 0 {
+		r.errorf("stats.retention_days must not be negative, got %d", c.Stats.RetentionDays)
+	}
+	if c.SMS.QueueFile == "" {
+		r.warnf("sms.queue_file is empty - SMS jobs still queued at shutdown will be lost")
+	}
+	if c.SMS.PauseFile == "" {
+		r.warnf("sms.pause_file is empty - a kill switch pause ends when the service restarts")
+	}
+	if c.Drip.Enabled && c.Drip.FilePath == "" {
+		r.warnf("drip.file_path is empty - follow-up SMS still scheduled are lost on restart")
+	}
+	if c.OptOut.FilePath == "" {
+		r.warnf("optout.file_path is empty - opt-outs will be lost on restart and customers messaged again")
+	}
+	if c.Audit.FilePath == "" {
+		r.warnf("audit.file_path is empty - the audit log will be lost on restart")
+	}
+
+	if c.Report.Enabled {
+		if _, err := time.Parse("15:04", c.Report.Time); err != nil {
+			r.errorf("report.time must be HH:MM, got %q", c.Report.Time)
+		}
+	}
+}
+
+func (c *Config) validateTelegram(r *ValidationResult) {
+	t := c.Telegram
+
+	if t.Token == "" {
+		if t.Embedded {
+			r.warnf("telegram.token is empty - the embedded bot will not start")
+		}
+	}
+
+	if len(t.Admins) == 0 {
+		r.warnf("telegram.admins is empty - nobody can use the bot")
+	}
+
+	ids := make(map[int64]bool)
+	owners := 0
+	for i, admin := range t.Admins {
+		if admin.ID <= 0 {
+			r.errorf("telegram.admins[%d].id must be a positive Telegram user ID, got %d", i, admin.ID)
+		}
+		if ids[admin.ID] {
+			r.errorf("telegram.admins[%d].id %d is listed more than once", i, admin.ID)
+		}
+		ids[admin.ID] = true
+
+		if admin.Name == "" {
+			r.warnf("telegram.admins[%d].name is empty", i)
+		}
+		switch admin.Language {
+		case "", "fa", "en":
+		default:
+			r.warnf("telegram.admins[%d].language %q is not fa or en - Persian will be used", i, admin.Language)
+		}
+		if admin.Owner {
+			owners++
+		}
+	}
+	if len(t.Admins) > 0 && owners == 0 {
+		r.warnf("telegram.admins has no owner - nobody can pause SMS")
+	}
+
+	if t.UndoWindowMinutes < 0 {
+		r.errorf("telegram.undo_window_minutes must not be negative, got %d", t.UndoWindowMinutes)
+	}
+
+	w := t.Webhook
+	if (w.URL == "") != (w.Path == "") {
+		r.warnf("telegram.webhook needs both url and path - falling back to long polling")
+	}
+	if w.URL != "" {
+		if u, err := url.Parse(w.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			r.errorf("telegram.webhook.url must be an https URL, got %q", w.URL)
+		}
+	}
+	if w.Path != "" && !strings.HasPrefix(w.Path, "/") {
+		r.errorf("telegram.webhook.path must start with /, got %q", w.Path)
+	}
+	if w.Enabled() {
+		if w.SecretToken == "" {
+			r.warnf("telegram.webhook.secret_token is empty - anyone who learns the path can send updates")
+		} else if !secretTokenPattern.MatchString(w.SecretToken) {
+			r.errorf("telegram.webhook.secret_token may only contain A-Z, a-z, 0-9, _ and - (1-256 characters)")
+		}
+	}
+}
@@ -0,0 +1,474 @@
+package drip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/utils"
+	"novinhub-webhook/pkg/logger"
+)
+
+// Enrollment statuses
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed" // Every step was sent, failed or skipped
+	StatusConverted = "converted" // The lead bought; remaining steps were skipped
+	StatusCancelled = "cancelled" // The phone opted out; remaining steps were skipped
+)
+
+// Step statuses
+const (
+	StepPending = "pending"
+	StepSent    = "sent"
+	StepFailed  = "failed"
+	StepSkipped = "skipped"
+)
+
+const (
+	// checkInterval is how often the scheduler looks for due steps
+	checkInterval = time.Minute
+	// finishedRetention is how long finished enrollments are kept for the bot and the admin API
+	finishedRetention = 30 * 24 * time.Hour
+)
+
+// ErrNotEnrolled is returned when a phone has no active sequence to stop
+var ErrNotEnrolled = errors.New("phone has no active follow-up sequence")
+
+// StepState is the progress of one follow-up SMS
+type StepState struct {
+	Pattern string    `json:"pattern,omitempty"` // Empty sends the active daily pattern
+	DueAt   time.Time `json:"due_at"`
+	SentAt  time.Time `json:"sent_at,omitempty"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Enrollment is a lead's progress through a follow-up sequence
+type Enrollment struct {
+	Phone     string      `json:"phone"`
+	UserID    string      `json:"user_id"`
+	LeadID    string      `json:"lead_id,omitempty"`
+	Platform  string      `json:"platform,omitempty"`
+	Sequence  string      `json:"sequence"`
+	StartedAt time.Time   `json:"started_at"`
+	Steps     []StepState `json:"steps"`
+	Status    string      `json:"status"`
+	StoppedAt time.Time   `json:"stopped_at,omitempty"`
+	StoppedBy string      `json:"stopped_by,omitempty"`
+}
+
+// Active reports whether follow-ups are still scheduled
+func (e Enrollment) Active() bool {
+	return e.Status == StatusActive
+}
+
+// NextStep returns the index of the first pending step, or -1
+func (e Enrollment) NextStep() int {
+	for i, step := range e.Steps {
+		if step.Status == StepPending {
+			return i
+		}
+	}
+	return -1
+}
+
+// Scheduler sends the follow-up SMS of each enrolled lead when they fall due
+// Progress is saved as a JSON file after every change and reread when another process changed it
+type Scheduler struct {
+	logger     *logger.Logger
+	config     *config.Config
+	smsService *services.SMSService
+	filePath   string
+
+	mu          sync.Mutex
+	enrollments map[string]*Enrollment // By normalized phone; one per phone, the most recent
+	modTime     time.Time              // Of the file when last read or written
+}
+
+// NewScheduler creates the drip scheduler and loads the saved enrollments
+func NewScheduler(logger *logger.Logger, cfg *config.Config, smsService *services.SMSService) (*Scheduler, error) {
+	s := &Scheduler{
+		logger:      logger,
+		config:      cfg,
+		smsService:  smsService,
+		filePath:    cfg.Drip.FilePath,
+		enrollments: make(map[string]*Enrollment),
+	}
+
+	if s.filePath == "" {
+		logger.Warn("⚠️ Drip file path not configured - follow-up progress will be kept in memory only")
+		return s, nil
+	}
+
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("💧 Drip scheduler initialized",
+		"enabled", cfg.DripEnabled(),
+		"file_path", s.filePath,
+		"enrollments", len(s.enrollments))
+
+	return s, nil
+}
+
+// loadLocked reads the saved enrollments; callers hold mu or own s exclusively
+func (s *Scheduler) loadLocked() error {
+	info, err := os.Stat(s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read drip file: %w", err)
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read drip file: %w", err)
+	}
+
+	var enrollments []Enrollment
+	if err := json.Unmarshal(data, &enrollments); err != nil {
+		return fmt.Errorf("failed to parse drip file: %w", err)
+	}
+
+	s.enrollments = make(map[string]*Enrollment, len(enrollments))
+	for i := range enrollments {
+		s.enrollments[enrollments[i].Phone] = &enrollments[i]
+	}
+	s.modTime = info.ModTime()
+
+	return nil
+}
+
+// refreshLocked rereads the file if another process (e.g. the standalone bot) saved it since; callers hold mu
+func (s *Scheduler) refreshLocked() {
+	if s.filePath == "" {
+		return
+	}
+
+	info, err := os.Stat(s.filePath)
+	if err != nil || !info.ModTime().After(s.modTime) {
+		return
+	}
+
+	if err := s.loadLocked(); err != nil {
+		s.logger.Error("Failed to reload drip file - keeping current progress", "error", err)
+	}
+}
+
+// saveLocked drops long-finished enrollments and writes the rest atomically; callers hold mu
+func (s *Scheduler) saveLocked() {
+	cutoff := time.Now().Add(-finishedRetention)
+	for phone, enrollment := range s.enrollments {
+		if !enrollment.Active() && enrollment.StoppedAt.Before(cutoff) {
+			delete(s.enrollments, phone)
+		}
+	}
+
+	if s.filePath == "" {
+		return
+	}
+
+	if err := s.writeLocked(); err != nil {
+		s.logger.Error("Failed to save drip progress", "error", err, "file_path", s.filePath)
+	}
+}
+
+// writeLocked writes every enrollment to the file; callers hold mu
+func (s *Scheduler) writeLocked() error {
+	data, err := json.MarshalIndent(s.sortedLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode drip progress: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create drip directory: %w", err)
+	}
+
+	tmp := s.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write drip progress: %w", err)
+	}
+	if err := os.Rename(tmp, s.filePath); err != nil {
+		return fmt.Errorf("failed to replace drip file: %w", err)
+	}
+
+	if info, err := os.Stat(s.filePath); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// sortedLocked returns copies of every enrollment, newest first; callers hold mu
+func (s *Scheduler) sortedLocked() []Enrollment {
+	enrollments := make([]Enrollment, 0, len(s.enrollments))
+	for _, enrollment := range s.enrollments {
+		enrollments = append(enrollments, copyEnrollment(enrollment))
+	}
+	sort.Slice(enrollments, func(i, j int) bool {
+		return enrollments[i].StartedAt.After(enrollments[j].StartedAt)
+	})
+	return enrollments
+}
+
+// copyEnrollment returns a copy that doesn't share its steps with the scheduler
+func copyEnrollment(e *Enrollment) Enrollment {
+	c := *e
+	c.Steps = append([]StepState(nil), e.Steps...)
+	return c
+}
+
+// Enroll starts the follow-up sequence matching the lead's platform, timed from now
+// Nothing happens if SMS or drip is disabled, no sequence matches or the phone already has an active sequence
+func (s *Scheduler) Enroll(phone, userID, leadID, platform string) bool {
+	// The lead's first SMS was skipped too when sending is disabled
+	if s == nil || !s.config.SMSEnabled() {
+		return false
+	}
+
+	sequence, ok := s.config.DripSequence(platform)
+	if !ok || len(sequence.Steps) == 0 {
+		return false
+	}
+
+	phone = utils.NormalizeIranianPhone(phone)
+	if phone == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+	if existing, ok := s.enrollments[phone]; ok && existing.Active() {
+		return false
+	}
+
+	now := time.Now()
+	enrollment := &Enrollment{
+		Phone:     phone,
+		UserID:    userID,
+		LeadID:    leadID,
+		Platform:  platform,
+		Sequence:  sequence.Name,
+		StartedAt: now,
+		Status:    StatusActive,
+	}
+	for _, step := range sequence.Steps {
+		enrollment.Steps = append(enrollment.Steps, StepState{
+			Pattern: step.Pattern,
+			DueAt:   now.Add(step.Delay()),
+			Status:  StepPending,
+		})
+	}
+	s.enrollments[phone] = enrollment
+	s.saveLocked()
+
+	s.logger.Info("💧 LEAD ENROLLED IN FOLLOW-UP SEQUENCE",
+		"phone", phone,
+		"lead_id", leadID,
+		"sequence", sequence.Name,
+		"steps", len(enrollment.Steps),
+		"first_due", enrollment.Steps[0].DueAt.Format(time.RFC3339))
+
+	return true
+}
+
+// EnrollQueued enrolls the lead of a queued first SMS once the queue worker has sent it
+func (s *Scheduler) EnrollQueued(job services.SMSJob) {
+	s.Enroll(job.Phone, job.UserID, job.LeadID, job.Platform)
+}
+
+// MarkConverted stops the phone's active sequence because the lead bought
+func (s *Scheduler) MarkConverted(phone, by string) (Enrollment, error) {
+	return s.stop(phone, StatusConverted, by)
+}
+
+// stop ends the phone's active sequence with status, skipping the steps not yet sent
+func (s *Scheduler) stop(phone, status, by string) (Enrollment, error) {
+	phone = utils.NormalizeIranianPhone(phone)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+	enrollment, ok := s.enrollments[phone]
+	if !ok || !enrollment.Active() {
+		return Enrollment{}, ErrNotEnrolled
+	}
+
+	s.finishLocked(enrollment, status, by)
+	s.saveLocked()
+
+	s.logger.Info("🛑 FOLLOW-UP SEQUENCE STOPPED",
+		"phone", phone,
+		"sequence", enrollment.Sequence,
+		"status", status,
+		"by", by)
+
+	return copyEnrollment(enrollment), nil
+}
+
+// finishLocked ends an enrollment, skipping its pending steps; callers hold mu
+func (s *Scheduler) finishLocked(enrollment *Enrollment, status, by string) {
+	for i := range enrollment.Steps {
+		if enrollment.Steps[i].Status == StepPending {
+			enrollment.Steps[i].Status = StepSkipped
+		}
+	}
+	enrollment.Status = status
+	enrollment.StoppedAt = time.Now()
+	enrollment.StoppedBy = by
+}
+
+// Progress returns the phone's most recent enrollment, active or finished
+func (s *Scheduler) Progress(phone string) (Enrollment, bool) {
+	if s == nil {
+		return Enrollment{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+	enrollment, ok := s.enrollments[utils.NormalizeIranianPhone(phone)]
+	if !ok {
+		return Enrollment{}, false
+	}
+	return copyEnrollment(enrollment), true
+}
+
+// Active returns the enrollments with follow-ups still scheduled, newest first
+func (s *Scheduler) Active() []Enrollment {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+	var active []Enrollment
+	for _, enrollment := range s.sortedLocked() {
+		if enrollment.Active() {
+			active = append(active, enrollment)
+		}
+	}
+	return active
+}
+
+// Run sends due follow-ups until ctx is cancelled; while drip is disabled pending steps wait
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !s.config.DripEnabled() {
+			continue
+		}
+
+		s.sendDue(ctx, time.Now())
+	}
+}
+
+// dueStep identifies a follow-up to send
+type dueStep struct {
+	phone   string
+	userID  string
+	pattern string
+	index   int
+}
+
+// sendDue sends the follow-ups due at now, one step per lead per round
+func (s *Scheduler) sendDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	s.refreshLocked()
+	var due []dueStep
+	for phone, enrollment := range s.enrollments {
+		next := enrollment.NextStep()
+		if !enrollment.Active() || next < 0 || now.Before(enrollment.Steps[next].DueAt) {
+			continue
+		}
+		due = append(due, dueStep{
+			phone:   phone,
+			userID:  enrollment.UserID,
+			pattern: enrollment.Steps[next].Pattern,
+			index:   next,
+		})
+	}
+	s.mu.Unlock()
+
+	for _, step := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := s.smsService.SendFollowUpContext(ctx, step.phone, step.userID, step.pattern)
+		s.record(step, err)
+	}
+}
+
+// record applies the outcome of a follow-up send to its enrollment
+func (s *Scheduler) record(step dueStep, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refreshLocked()
+	enrollment, ok := s.enrollments[step.phone]
+	if !ok || step.index >= len(enrollment.Steps) || enrollment.Steps[step.index].Status != StepPending {
+		// Stopped while sending
+		return
+	}
+	state := &enrollment.Steps[step.index]
+
+	var quiet *services.QuietHoursError
+	switch {
+	case errors.Is(err, services.ErrSMSPaused), errors.Is(err, services.ErrSMSDisabled), errors.Is(err, context.Canceled):
+		// Try again on a later round
+		return
+	case errors.As(err, &quiet):
+		state.DueAt = quiet.Until
+		s.logger.Info("🌙 FOLLOW-UP DEFERRED FOR QUIET HOURS",
+			"phone", step.phone,
+			"until", quiet.Until.Format(time.RFC3339))
+	case errors.Is(err, services.ErrOptedOut):
+		s.finishLocked(enrollment, StatusCancelled, "opt_out")
+		s.logger.Info("🚫 FOLLOW-UP SEQUENCE CANCELLED - PHONE OPTED OUT", "phone", step.phone)
+	case err != nil:
+		state.Status = StepFailed
+		state.Error = err.Error()
+		s.logger.Error("Failed to send follow-up SMS",
+			"error", err,
+			"phone", step.phone,
+			"sequence", enrollment.Sequence,
+			"step", step.index+1)
+	default:
+		state.Status = StepSent
+		state.SentAt = time.Now()
+		s.logger.Info("✅ FOLLOW-UP SMS SENT",
+			"phone", step.phone,
+			"sequence", enrollment.Sequence,
+			"step", step.index+1)
+	}
+
+	if enrollment.Active() && enrollment.NextStep() < 0 {
+		s.finishLocked(enrollment, StatusCompleted, "")
+	}
+	s.saveLocked()
+}
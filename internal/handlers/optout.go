@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/optout"
+	"novinhub-webhook/pkg/logger"
+)
+
+// OptOutPath is where the opt-out admin API is served
+const OptOutPath = "/admin/optout"
+
+// apiActor is the audit actor recorded for admin API changes
+const apiActor = "admin_api"
+
+// OptOutHandler serves the opt-out list over the admin API
+//
+//	GET    /admin/optout             list entries (?format=csv for a CSV export)
+//	POST   /admin/optout             add {"phone": "...", "reason": "..."}
+//	DELETE /admin/optout/{phone}     remove a phone
+//	POST   /admin/optout/import      import a CSV body (phone[,reason] per line)
+type OptOutHandler struct {
+	logger   *logger.Logger
+	config   *config.Config
+	list     *optout.List
+	auditLog *audit.Log
+	token    []byte
+}
+
+// optOutRequest is the body of an add request
+type optOutRequest struct {
+	Phone  string `json:"phone"`
+	Reason string `json:"reason"`
+}
+
+// NewOptOutHandler creates the opt-out admin API handler, authenticated by admin_api.token
+func NewOptOutHandler(logger *logger.Logger, cfg *config.Config, list *optout.List, auditLog *audit.Log) *OptOutHandler {
+	return &OptOutHandler{
+		logger:   logger,
+		config:   cfg,
+		list:     list,
+		auditLog: auditLog,
+		token:    []byte(cfg.AdminAPI.Token),
+	}
+}
+
+// ServeHTTP routes an admin API request under OptOutPath
+func (h *OptOutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(h.logger, w, r, h.token) {
+		return
+	}
+
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, OptOutPath), "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		h.listEntries(w, r)
+	case rest == "" && r.Method == http.MethodPost:
+		h.add(w, r)
+	case rest == "import" && r.Method == http.MethodPost:
+		h.importCSV(w, r)
+	case rest != "" && rest != "import" && r.Method == http.MethodDelete:
+		h.remove(w, rest)
+	case rest == "" || rest == "import" || r.Method == http.MethodDelete:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorizeAdmin checks the bearer token in constant time, answering 401 when it doesn't match
+func authorizeAdmin(logger *logger.Logger, w http.ResponseWriter, r *http.Request, want []byte) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if ok && len(want) > 0 && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), want) == 1 {
+		return true
+	}
+
+	logger.Warn("🔒 Unauthorized admin API request", "path", r.URL.Path, "remote_addr", r.RemoteAddr)
+	w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+	http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	return false
+}
+
+// listEntries returns every entry as JSON, or as CSV with ?format=csv
+func (h *OptOutHandler) listEntries(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("format") == "csv" {
+		data, err := h.list.ExportCSV()
+		if err != nil {
+			h.logger.Error("Failed to export opt-out list", "error", err)
+			http.Error(w, "Failed to export opt-out list", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="optout.csv"`)
+		w.Write(data)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":   h.list.Len(),
+		"entries": h.list.Entries(),
+	})
+}
+
+// add opts a phone out
+func (h *OptOutHandler) add(w http.ResponseWriter, r *http.Request) {
+	var req optOutRequest
+	body := http.MaxBytesReader(w, r.Body, h.config.Webhook.MaxRequestSize)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	added, err := h.list.Add(optout.Entry{
+		Phone:   req.Phone,
+		AddedBy: apiActor,
+		Source:  optout.SourceAPI,
+		Reason:  req.Reason,
+	})
+	if errors.Is(err, optout.ErrInvalidPhone) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to add opt-out", "error", err, "phone", req.Phone)
+		http.Error(w, "Failed to save opt-out list", http.StatusInternalServerError)
+		return
+	}
+
+	entry, _ := h.list.Get(req.Phone)
+	if !added {
+		writeJSON(w, http.StatusOK, entry)
+		return
+	}
+
+	h.auditLog.Record(audit.Entry{
+		Actor:  apiActor,
+		Action: audit.ActionOptOutAdd,
+		After:  entry.Phone,
+		Detail: entry.Reason,
+		Source: audit.SourceAPI,
+	})
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// remove takes a phone off the list
+func (h *OptOutHandler) remove(w http.ResponseWriter, phone string) {
+	removed, err := h.list.Remove(phone)
+	if err != nil {
+		h.logger.Error("Failed to remove opt-out", "error", err, "phone", phone)
+		http.Error(w, "Failed to save opt-out list", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "Phone is not on the opt-out list", http.StatusNotFound)
+		return
+	}
+
+	h.auditLog.Record(audit.Entry{
+		Actor:  apiActor,
+		Action: audit.ActionOptOutRemove,
+		Before: phone,
+		Source: audit.SourceAPI,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// importCSV adds every phone in a CSV request body
+func (h *OptOutHandler) importCSV(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, h.config.Webhook.MaxRequestSize)
+	result, err := h.list.ImportCSV(body, apiActor, optout.SourceImport)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.logger.Error("Failed to import opt-out list", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.auditLog.Record(audit.Entry{
+		Actor:  apiActor,
+		Action: audit.ActionOptOutImport,
+		Detail: fmt.Sprintf("added=%d skipped=%d invalid=%d", result.Added, result.Skipped, len(result.Invalid)),
+		Source: audit.SourceAPI,
+	})
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeJSON writes value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"novinhub-webhook/internal/audit"
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
+	"novinhub-webhook/internal/utils"
+	"novinhub-webhook/pkg/logger"
+)
+
+// SequencesPath is where the follow-up sequence admin API is served
+const SequencesPath = "/admin/sequences"
+
+// SequenceHandler serves per-lead follow-up progress over the admin API
+//
+//	GET  /admin/sequences                     list leads with follow-ups still scheduled
+//	GET  /admin/sequences/{phone}             a phone's most recent sequence
+//	POST /admin/sequences/{phone}/converted   mark the lead converted, stopping its follow-ups
+type SequenceHandler struct {
+	logger    *logger.Logger
+	scheduler *drip.Scheduler
+	auditLog  *audit.Log
+	token     []byte
+}
+
+// NewSequenceHandler creates the follow-up sequence admin API handler, authenticated by admin_api.token
+func NewSequenceHandler(logger *logger.Logger, cfg *config.Config, scheduler *drip.Scheduler, auditLog *audit.Log) *SequenceHandler {
+	return &SequenceHandler{
+		logger:    logger,
+		scheduler: scheduler,
+		auditLog:  auditLog,
+		token:     []byte(cfg.AdminAPI.Token),
+	}
+}
+
+// ServeHTTP routes an admin API request under SequencesPath
+func (h *SequenceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdmin(h.logger, w, r, h.token) {
+		return
+	}
+
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, SequencesPath), "/")
+	phone, action, _ := strings.Cut(rest, "/")
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		active := h.scheduler.Active()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"count":       len(active),
+			"enrollments": active,
+		})
+	case action == "" && r.Method == http.MethodGet:
+		h.progress(w, phone)
+	case action == "converted" && r.Method == http.MethodPost:
+		h.markConverted(w, phone)
+	case action == "" || action == "converted":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// progress returns a phone's most recent sequence
+func (h *SequenceHandler) progress(w http.ResponseWriter, phone string) {
+	if utils.NormalizeIranianPhone(phone) == "" {
+		http.Error(w, "Invalid phone number", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, ok := h.scheduler.Progress(phone)
+	if !ok {
+		http.Error(w, "Phone has no follow-up sequence", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, enrollment)
+}
+
+// markConverted stops the follow-ups of a lead that bought
+func (h *SequenceHandler) markConverted(w http.ResponseWriter, phone string) {
+	if utils.NormalizeIranianPhone(phone) == "" {
+		http.Error(w, "Invalid phone number", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := h.scheduler.MarkConverted(phone, apiActor)
+	if err != nil {
+		// The only failure is drip.ErrNotEnrolled
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	h.auditLog.Record(audit.Entry{
+		Actor:  apiActor,
+		Action: audit.ActionLeadConverted,
+		After:  enrollment.Phone,
+		Detail: enrollment.Sequence,
+		Source: audit.SourceAPI,
+	})
+	writeJSON(w, http.StatusOK, enrollment)
+}
@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
 	"novinhub-webhook/internal/models"
+	"novinhub-webhook/internal/optout"
 	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/stats"
 	"novinhub-webhook/internal/utils"
 	"novinhub-webhook/pkg/logger"
 )
@@ -25,16 +31,22 @@ type SMSCache struct {
 // WebhookHandler handles incoming webhook requests
 type WebhookHandler struct {
 	logger     *logger.Logger
+	config     *config.Config
 	smsService *services.SMSService
+	stats      *stats.Store
+	drip       *drip.Scheduler
 	smsCache   map[string]SMSCache // key: phone_userID, value: cache entry
 	cacheMutex sync.RWMutex        // mutex for thread-safe cache operations
 }
 
 // NewWebhookHandler creates a new webhook handler
-func NewWebhookHandler(logger *logger.Logger, cfg *config.Config) *WebhookHandler {
+func NewWebhookHandler(logger *logger.Logger, cfg *config.Config, smsService *services.SMSService, store *stats.Store, scheduler *drip.Scheduler) *WebhookHandler {
 	return &WebhookHandler{
 		logger:     logger,
-		smsService: services.NewSMSService(logger, cfg),
+		config:     cfg,
+		smsService: smsService,
+		stats:      store,
+		drip:       scheduler,
 		smsCache:   make(map[string]SMSCache),
 		cacheMutex: sync.RWMutex{},
 	}
@@ -137,9 +149,19 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Read the raw body first for logging
+	// Read the raw body first for logging, refusing anything over webhook.max_request_size
+	r.Body = http.MaxBytesReader(w, r.Body, h.config.Webhook.MaxRequestSize)
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.logger.Warn("🚫 Webhook request body too large",
+				"limit_bytes", tooLarge.Limit,
+				"content_length", r.ContentLength,
+				"remote_addr", r.RemoteAddr)
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		h.logger.Error("Failed to read request body", "error", err)
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
@@ -168,6 +190,17 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 		"timestamp", time.Now().UTC(),
 		"raw_payload", string(rawPayload))
 
+	h.stats.Record(stats.Event{
+		Kind:      stats.KindWebhook,
+		EventType: event.Type,
+		UserID:    event.UserID.String(),
+	})
+
+	// Bound the processing of this event, including SMS sends; a client that disconnects
+	// early does not cancel a send already under way
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.processingTimeout())
+	defer cancel()
+
 	// Process different event types
 	switch event.Type {
 	case "message_created":
@@ -177,13 +210,20 @@ func (h *WebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
 	case "autoform_completed":
 		h.handleAutoformCompleted(event)
 	case "leed_created":
-		h.handleLeadCreated(event)
+		h.handleLeadCreated(ctx, event)
 	case "revalidate":
 		h.handleRevalidate(event)
 	default:
 		h.logger.Warn("Unknown event type received", "type", event.Type)
 	}
 
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		h.logger.Error("⏱️ WEBHOOK PROCESSING TIMED OUT",
+			"type", event.Type,
+			"user_id", event.UserID.String(),
+			"timeout", h.processingTimeout().String())
+	}
+
 	// Return 200 OK as required by NovinHub
 	w.WriteHeader(http.StatusOK)
 	response := models.WebhookResponse{
@@ -242,10 +282,68 @@ func (h *WebhookHandler) handleMessageCreated(event models.WebhookEvent) {
 		}
 	}
 
+	h.applyOptOutKeywords(event, message)
+
 	// Add your business logic here for handling new messages
 	// For example: save to database, send notifications, etc.
 }
 
+// applyOptOutKeywords opts the sender out when a message is, or starts with, an opt-out keyword
+// The phones in the message are used, falling back to the phones of the sender's earlier leads
+func (h *WebhookHandler) applyOptOutKeywords(event models.WebhookEvent, message models.Message) {
+	optOuts := h.smsService.OptOuts()
+	if optOuts == nil || !optOuts.MatchesKeyword(message.Text) {
+		return
+	}
+
+	senderID := socialUserID(message.SocialUser)
+	var phones []string
+	for _, phone := range utils.ExtractIranianPhoneNumbers(message.Text) {
+		if utils.IsValidIranianPhone(phone) {
+			phones = append(phones, phone)
+		}
+	}
+	if len(phones) == 0 && senderID != "" {
+		phones = h.stats.PhonesBySocialUser(senderID)
+	}
+
+	if len(phones) == 0 {
+		h.logger.Warn("🚫 OPT-OUT KEYWORD RECEIVED - NO PHONE KNOWN FOR SENDER",
+			"message_id", message.ID,
+			"social_user_id", senderID,
+			"user_id", event.UserID.String())
+		return
+	}
+
+	for _, phone := range phones {
+		_, err := optOuts.Add(optout.Entry{
+			Phone:   phone,
+			AddedBy: senderID,
+			Source:  optout.SourceKeyword,
+			Reason:  message.Text,
+		})
+		if err != nil {
+			h.logger.Error("Failed to opt out phone", "error", err, "phone", phone, "message_id", message.ID)
+		}
+	}
+}
+
+// socialUserID extracts the id of a social media user from its webhook payload
+func socialUserID(socialUser interface{}) string {
+	data, ok := socialUser.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	switch id := data["id"].(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
+
 // handleCommentCreated processes comment_created events
 func (h *WebhookHandler) handleCommentCreated(event models.WebhookEvent) {
 	h.logger.Info("Processing comment_created event", "user_id", event.UserID.String())
@@ -298,8 +396,13 @@ func (h *WebhookHandler) handleAutoformCompleted(event models.WebhookEvent) {
 	// Add your business logic here for handling completed forms
 }
 
-// handleLeadCreated processes leed_created events
-func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
+// processingTimeout returns how long a single webhook event may take to process
+func (h *WebhookHandler) processingTimeout() time.Duration {
+	return time.Duration(h.config.Webhook.ProcessingTimeout) * time.Second
+}
+
+// handleLeadCreated processes leed_created events; ctx bounds the SMS send
+func (h *WebhookHandler) handleLeadCreated(ctx context.Context, event models.WebhookEvent) {
 	h.logger.Info("Processing leed_created event", "user_id", event.UserID.String())
 
 	// Parse the lead payload
@@ -322,10 +425,22 @@ func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
 		"message_id", lead.MessageID,
 		"social_user", lead.SocialUser)
 
+	validPhone := lead.Type == "number" && utils.IsValidIranianPhone(lead.Value)
+	h.stats.Record(stats.Event{
+		Kind:         stats.KindLead,
+		Platform:     leadPlatform(lead),
+		SocialUserID: socialUserID(lead.SocialUser),
+		Phone:        utils.NormalizeIranianPhone(lead.Value),
+		UserID:       event.UserID.String(),
+		LeadID:       lead.ID,
+		LeadType:     lead.Type,
+		Valid:        validPhone,
+	})
+
 	// Process phone number leads specifically
 	if lead.Type == "number" && lead.Value != "" {
 		// Validate if it's a valid Iranian phone number
-		if utils.IsValidIranianPhone(lead.Value) {
+		if validPhone {
 			h.logger.Warn("🎯 LEAD WITH VALID PHONE NUMBER DETECTED! 🎯",
 				"phone", lead.Value,
 				"lead_id", lead.ID,
@@ -335,12 +450,55 @@ func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
 			// Check if we should send SMS (deduplication)
 			if h.shouldSendSMS(lead.Value, event.UserID.String()) {
 				// Call SMS service to send pattern-based SMS
-				err := h.smsService.SendSMSWithPattern(
+				err := h.smsService.SendSMSWithPatternContext(
+					ctx,
 					lead.Value,
 					event.UserID.String(),
 				)
 
-				if err != nil {
+				var quiet *services.QuietHoursError
+				if errors.Is(err, services.ErrSMSPaused) {
+					// Sending is paused - hold the lead and mark it so duplicates aren't queued twice
+					// Follow-ups start when the queue worker sends it
+					h.smsService.Enqueue(services.SMSJob{
+						Phone:    lead.Value,
+						UserID:   event.UserID.String(),
+						LeadID:   lead.ID,
+						Platform: leadPlatform(lead),
+						Reason:   "paused",
+					})
+					h.markSMSSent(lead.Value, event.UserID.String())
+				} else if errors.As(err, &quiet) {
+					// Quiet hours - send when they end
+					h.smsService.Enqueue(services.SMSJob{
+						Phone:     lead.Value,
+						UserID:    event.UserID.String(),
+						LeadID:    lead.ID,
+						Platform:  leadPlatform(lead),
+						Reason:    "quiet_hours",
+						NotBefore: quiet.Until,
+					})
+					h.markSMSSent(lead.Value, event.UserID.String())
+				} else if errors.Is(err, services.ErrOptedOut) {
+					// Already recorded by the SMS service
+					h.logger.Info("⏭️ SMS SKIPPED - PHONE OPTED OUT",
+						"phone", lead.Value,
+						"lead_id", lead.ID,
+						"user_id", event.UserID.String())
+				} else if errors.Is(err, services.ErrSMSLimited) {
+					// Already recorded by the SMS service
+					h.logger.Info("⏭️ SMS SKIPPED - PHONE LIMIT REACHED",
+						"phone", lead.Value,
+						"lead_id", lead.ID,
+						"user_id", event.UserID.String(),
+						"reason", err)
+				} else if services.IsTimeout(err) {
+					h.logger.Error("⏱️ SMS for lead timed out",
+						"error", err,
+						"phone", lead.Value,
+						"lead_id", lead.ID,
+						"timeout", h.processingTimeout().String())
+				} else if err != nil {
 					h.logger.Error("Failed to send SMS for lead",
 						"error", err,
 						"phone", lead.Value,
@@ -349,6 +507,9 @@ func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
 					// Mark SMS as sent to prevent duplicates
 					h.markSMSSent(lead.Value, event.UserID.String())
 
+					// Schedule the follow-ups of the lead's sequence, if any
+					h.drip.Enroll(lead.Value, event.UserID.String(), lead.ID, leadPlatform(lead))
+
 					h.logger.Info("✅ SMS PROCESSING COMPLETED FOR LEAD",
 						"phone", lead.Value,
 						"lead_id", lead.ID,
@@ -359,6 +520,13 @@ func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
 					"phone", lead.Value,
 					"lead_id", lead.ID,
 					"user_id", event.UserID.String())
+
+				h.stats.Record(stats.Event{
+					Kind:   stats.KindSMSDuplicate,
+					Phone:  utils.NormalizeIranianPhone(lead.Value),
+					UserID: event.UserID.String(),
+					LeadID: lead.ID,
+				})
 			}
 		} else {
 			h.logger.Warn("Invalid phone number in lead",
@@ -370,6 +538,31 @@ func (h *WebhookHandler) handleLeadCreated(event models.WebhookEvent) {
 	// Add your business logic here for handling new leads
 }
 
+// leadPlatform extracts the social platform name from a lead's social user or message data
+func leadPlatform(lead models.Lead) string {
+	for _, source := range []interface{}{lead.SocialUser, lead.Message} {
+		data, ok := source.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"platform", "social_type", "type"} {
+			if value, ok := data[key].(string); ok && value != "" {
+				return value
+			}
+		}
+		// The platform is sometimes only present on the nested account object
+		if account, ok := data["account"].(map[string]interface{}); ok {
+			for _, key := range []string{"platform", "type"} {
+				if value, ok := account[key].(string); ok && value != "" {
+					return value
+				}
+			}
+		}
+	}
+
+	return "unknown"
+}
+
 // handleRevalidate processes revalidate events
 func (h *WebhookHandler) handleRevalidate(event models.WebhookEvent) {
 	h.logger.Info("Processing revalidate event", "user_id", event.UserID.String())
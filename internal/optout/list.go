@@ -0,0 +1,395 @@
+package optout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/utils"
+	"novinhub-webhook/pkg/logger"
+)
+
+// Sources an opt-out can come from
+const (
+	SourceBot     = "bot"
+	SourceAPI     = "api"
+	SourceImport  = "csv_import"
+	SourceKeyword = "keyword"
+)
+
+// ErrInvalidPhone is returned for numbers that are not Iranian mobile numbers
+var ErrInvalidPhone = errors.New("not a valid Iranian mobile number")
+
+// Entry is a phone number that must not be messaged
+type Entry struct {
+	Phone   string    `json:"phone"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by,omitempty"`
+	Source  string    `json:"source"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// ImportResult summarizes a CSV import
+type ImportResult struct {
+	Added   int      `json:"added"`
+	Skipped int      `json:"skipped"` // Already on the list
+	Invalid []string `json:"invalid,omitempty"`
+}
+
+// List is the do-not-contact list of normalized phone numbers, saved as a JSON file after every change
+// and reread when another process (the server or the standalone bot) saves it
+type List struct {
+	logger   *logger.Logger
+	filePath string
+	keywords []string
+
+	mu      sync.RWMutex
+	entries map[string]Entry
+	modTime time.Time // Of the file when last read or written
+}
+
+// NewList creates the opt-out list and loads the saved entries
+func NewList(logger *logger.Logger, cfg *config.Config) (*List, error) {
+	l := &List{
+		logger:   logger,
+		filePath: cfg.OptOut.FilePath,
+		entries:  make(map[string]Entry),
+	}
+	for _, keyword := range cfg.OptOut.Keywords {
+		if keyword = normalizeText(keyword); keyword != "" {
+			l.keywords = append(l.keywords, keyword)
+		}
+	}
+
+	if l.filePath == "" {
+		logger.Warn("⚠️ Opt-out file path not configured - opt-outs will be kept in memory only")
+		return l, nil
+	}
+
+	if err := l.loadLocked(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("🚫 Opt-out list initialized",
+		"file_path", l.filePath,
+		"entries", len(l.entries))
+
+	return l, nil
+}
+
+// loadLocked replaces the entries in memory with the saved ones; callers hold mu
+func (l *List) loadLocked() error {
+	info, err := os.Stat(l.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read opt-out list: %w", err)
+	}
+
+	data, err := os.ReadFile(l.filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read opt-out list: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse opt-out list: %w", err)
+	}
+	l.entries = make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		l.entries[entry.Phone] = entry
+	}
+	l.modTime = info.ModTime()
+
+	return nil
+}
+
+// refreshLocked rereads the file if another process (e.g. the standalone bot) saved it since; callers hold mu
+func (l *List) refreshLocked() {
+	if l.filePath == "" {
+		return
+	}
+
+	info, err := os.Stat(l.filePath)
+	if err != nil || !info.ModTime().After(l.modTime) {
+		return
+	}
+
+	if err := l.loadLocked(); err != nil {
+		l.logger.Error("Failed to reload opt-out list - keeping entries in memory", "error", err)
+	}
+}
+
+// refresh takes mu to pick up changes saved by another process before a read
+func (l *List) refresh() {
+	l.mu.Lock()
+	l.refreshLocked()
+	l.mu.Unlock()
+}
+
+// saveLocked writes the list atomically; callers hold mu
+func (l *List) saveLocked() error {
+	if l.filePath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(l.sortedLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode opt-out list: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create opt-out directory: %w", err)
+	}
+
+	tmp := l.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write opt-out list: %w", err)
+	}
+	if err := os.Rename(tmp, l.filePath); err != nil {
+		return fmt.Errorf("failed to replace opt-out list: %w", err)
+	}
+	if info, err := os.Stat(l.filePath); err == nil {
+		l.modTime = info.ModTime()
+	}
+
+	return nil
+}
+
+// Contains reports whether a phone number has opted out
+func (l *List) Contains(phone string) bool {
+	_, ok := l.Get(phone)
+	return ok
+}
+
+// Get returns the opt-out entry for a phone number
+func (l *List) Get(phone string) (Entry, bool) {
+	if l == nil {
+		return Entry{}, false
+	}
+
+	l.refresh()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entry, ok := l.entries[utils.NormalizeIranianPhone(phone)]
+	return entry, ok
+}
+
+// Add puts a phone number on the list; it returns false if it was already there
+func (l *List) Add(entry Entry) (bool, error) {
+	if !utils.IsValidIranianPhone(entry.Phone) {
+		return false, fmt.Errorf("%q: %w", entry.Phone, ErrInvalidPhone)
+	}
+	entry.Phone = utils.NormalizeIranianPhone(entry.Phone)
+	if entry.AddedAt.IsZero() {
+		entry.AddedAt = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshLocked()
+	if _, ok := l.entries[entry.Phone]; ok {
+		return false, nil
+	}
+
+	l.entries[entry.Phone] = entry
+	if err := l.saveLocked(); err != nil {
+		delete(l.entries, entry.Phone)
+		return false, err
+	}
+
+	l.logger.Info("🚫 PHONE OPTED OUT",
+		"phone", entry.Phone,
+		"source", entry.Source,
+		"added_by", entry.AddedBy)
+
+	return true, nil
+}
+
+// Remove takes a phone number off the list; it returns false if it was not there
+func (l *List) Remove(phone string) (bool, error) {
+	phone = utils.NormalizeIranianPhone(phone)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshLocked()
+	entry, ok := l.entries[phone]
+	if !ok {
+		return false, nil
+	}
+
+	delete(l.entries, phone)
+	if err := l.saveLocked(); err != nil {
+		l.entries[phone] = entry
+		return false, err
+	}
+
+	l.logger.Info("✅ PHONE OPT-OUT REMOVED", "phone", phone)
+	return true, nil
+}
+
+// Entries returns every entry, most recent first
+func (l *List) Entries() []Entry {
+	l.refresh()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.sortedLocked()
+}
+
+// Len returns the number of opted-out phone numbers
+func (l *List) Len() int {
+	l.refresh()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.entries)
+}
+
+// sortedLocked returns the entries most recent first; callers hold mu
+func (l *List) sortedLocked() []Entry {
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].AddedAt.Equal(entries[j].AddedAt) {
+			return entries[i].AddedAt.After(entries[j].AddedAt)
+		}
+		return entries[i].Phone < entries[j].Phone
+	})
+	return entries
+}
+
+// ImportCSV adds the phone numbers in the first column of a CSV file, with an optional reason in the second
+// A header row is skipped; the list is saved once at the end
+func (l *List) ImportCSV(r io.Reader, by, source string) (ImportResult, error) {
+	var result ImportResult
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	now := time.Now()
+	var added []Entry
+	for row := 0; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		phone := strings.TrimSpace(record[0])
+		if !utils.IsValidIranianPhone(phone) {
+			if row > 0 {
+				result.Invalid = append(result.Invalid, phone)
+			}
+			continue
+		}
+
+		entry := Entry{
+			Phone:   utils.NormalizeIranianPhone(phone),
+			AddedAt: now,
+			AddedBy: by,
+			Source:  source,
+		}
+		if len(record) > 1 {
+			entry.Reason = strings.TrimSpace(record[1])
+		}
+		added = append(added, entry)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refreshLocked()
+	var newPhones []string
+	for _, entry := range added {
+		if _, ok := l.entries[entry.Phone]; ok {
+			result.Skipped++
+			continue
+		}
+		l.entries[entry.Phone] = entry
+		newPhones = append(newPhones, entry.Phone)
+	}
+
+	if err := l.saveLocked(); err != nil {
+		for _, phone := range newPhones {
+			delete(l.entries, phone)
+		}
+		return ImportResult{}, err
+	}
+	result.Added = len(newPhones)
+
+	l.logger.Info("🚫 OPT-OUT LIST IMPORTED",
+		"added", result.Added,
+		"skipped", result.Skipped,
+		"invalid", len(result.Invalid),
+		"by", by)
+
+	return result, nil
+}
+
+// ExportCSV writes the list as CSV with a header row
+func (l *List) ExportCSV() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	writer.Write([]string{"phone", "reason", "source", "added_by", "added_at"})
+	for _, entry := range l.Entries() {
+		writer.Write([]string{entry.Phone, entry.Reason, entry.Source, entry.AddedBy, entry.AddedAt.Format(time.RFC3339)})
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// MatchesKeyword reports whether text is an opt-out keyword or starts with one as a whole word,
+// so "stop 09121234567" opts out but "don't stop sending me offers" does not
+func (l *List) MatchesKeyword(text string) bool {
+	if l == nil || len(l.keywords) == 0 {
+		return false
+	}
+
+	normalized := normalizeText(text)
+	for _, keyword := range l.keywords {
+		if normalized == keyword || strings.HasPrefix(normalized, keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// arabicLetters maps Arabic code points commonly typed in Persian text to their Persian forms
+var arabicLetters = strings.NewReplacer("ي", "ی", "ك", "ک")
+
+// normalizeText lowercases text and reduces it to words separated by single spaces
+func normalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(arabicLetters.Replace(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// corsPolicy decides which browser origins may call the service, from security.* settings
+type corsPolicy struct {
+	enabled     bool
+	allowAll    bool
+	exact       map[string]bool
+	wildcards   []wildcardOrigin
+	credentials bool
+	maxAge      int
+}
+
+// wildcardOrigin matches any subdomain of host for one scheme
+type wildcardOrigin struct {
+	scheme string
+	suffix string // ".example.com", optionally with a port
+}
+
+// newCORSPolicy parses the configured origins; invalid ones are reported by config validation and never match
+func newCORSPolicy(enabled bool, origins []string, credentials bool, maxAge int) *corsPolicy {
+	p := &corsPolicy{
+		enabled:     enabled,
+		allowAll:    len(origins) == 0,
+		exact:       make(map[string]bool),
+		credentials: credentials,
+		maxAge:      maxAge,
+	}
+
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimRight(origin, "/"))
+		if origin == "*" {
+			p.allowAll = true
+			continue
+		}
+
+		if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			p.wildcards = append(p.wildcards, wildcardOrigin{scheme: scheme, suffix: "." + host})
+			continue
+		}
+		p.exact[origin] = true
+	}
+
+	// Credentials are never sent to arbitrary origins
+	if p.allowAll {
+		p.credentials = false
+	}
+
+	return p
+}
+
+// allows reports whether a browser origin may read responses
+func (p *corsPolicy) allows(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.exact[origin] {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, wildcard := range p.wildcards {
+		if u.Scheme == wildcard.scheme && strings.HasSuffix(u.Host, wildcard.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// corsMiddleware adds CORS headers on browser-facing routes (health and admin API)
+// /webhook and other server-to-server routes never get them
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		group := ""
+		if route != nil {
+			group, _, _ = strings.Cut(route.GetName(), " ")
+		}
+		if !s.cors.enabled || (group != routeHealth && group != routeAdmin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		origin := r.Header.Get("Origin")
+		if origin != "" {
+			w.Header().Add("Vary", "Origin")
+		}
+		allowed := origin != "" && s.cors.allows(origin)
+
+		if allowed {
+			if s.cors.allowAll && !s.cors.credentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if s.cors.credentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		// Answer preflight requests here; the route handlers only see the real request
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				if s.cors.maxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(s.cors.maxAge))
+				}
+			} else {
+				s.logger.Warn("🚫 CORS preflight from disallowed origin", "origin", origin, "path", r.URL.Path)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
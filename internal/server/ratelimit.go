@@ -0,0 +1,170 @@
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Route names used to pick a rate limit
+const (
+	routeWebhook = "webhook"
+	routeHealth  = "health"
+	routeAdmin   = "admin"
+)
+
+// bucketIdleSweep is how often buckets of clients that went quiet are dropped
+const bucketIdleSweep = 5 * time.Minute
+
+// bucket is one client's token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a per-IP token bucket allowing limit() requests per minute with bursts of the same size
+// The limit is read on every request so config reloads apply immediately
+type rateLimiter struct {
+	limit func() int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a limiter reading its per-minute limit from limit
+func newRateLimiter(limit func() int) *rateLimiter {
+	return &rateLimiter{
+		limit:     limit,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow takes a token for ip, returning false and how long to wait when the bucket is empty
+func (l *rateLimiter) allow(ip string, now time.Time) (bool, time.Duration) {
+	limit := l.limit()
+	if limit <= 0 {
+		return true, 0
+	}
+	capacity := float64(limit)
+	perSecond := capacity / 60
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > bucketIdleSweep {
+		l.sweep(now, capacity, perSecond)
+	}
+
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[ip] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+		return false, wait
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have refilled completely; callers hold mu
+func (l *rateLimiter) sweep(now time.Time, capacity, perSecond float64) {
+	for ip, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*perSecond >= capacity {
+			delete(l.buckets, ip)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitMiddleware applies the limit of the matched route's group to each client IP
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Admin routes are named "admin <path>"; all of them share one limit
+		group, _, _ := strings.Cut(route.GetName(), " ")
+		limiter, ok := s.limiters[group]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := s.clientIP(r)
+		allowed, wait := limiter.allow(ip, time.Now())
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+
+			s.logger.Warn("🚦 Rate limit exceeded",
+				"ip", ip,
+				"route", group,
+				"path", r.URL.Path,
+				"retry_after", retryAfter)
+
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the address of the client, following X-Forwarded-For only through trusted proxies
+func (s *Server) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !s.isTrustedProxy(ip) {
+		return host
+	}
+
+	// Walk the chain from the nearest hop; the first address not added by a trusted proxy is the client
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+		host = hop.String()
+		if !s.isTrustedProxy(hop) {
+			break
+		}
+	}
+
+	return host
+}
+
+// isTrustedProxy reports whether ip is in security.trusted_proxies
+func (s *Server) isTrustedProxy(ip net.IP) bool {
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
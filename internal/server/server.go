@@ -1,12 +1,18 @@
 package server
 
 import (
-	"log"
+	"context"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/drip"
 	"novinhub-webhook/internal/handlers"
+	"novinhub-webhook/internal/services"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/internal/utils"
 	"novinhub-webhook/pkg/logger"
 
 	"github.com/gorilla/mux"
@@ -18,54 +24,95 @@ type Server struct {
 	logger  *logger.Logger
 	handler *handlers.WebhookHandler
 	health  *handlers.HealthHandler
+	routes  map[string]http.Handler
+	admin   map[string]http.Handler
+
+	limiters       map[string]*rateLimiter
+	trustedProxies []*net.IPNet
+	cors           *corsPolicy
+
+	mu         sync.Mutex
+	httpServer *http.Server
 }
 
 // New creates a new server instance
-func New(cfg *config.Config, log *logger.Logger) *Server {
-	webhookHandler := handlers.NewWebhookHandler(log, cfg)
+func New(cfg *config.Config, log *logger.Logger, smsService *services.SMSService, store *stats.Store, scheduler *drip.Scheduler) *Server {
+	webhookHandler := handlers.NewWebhookHandler(log, cfg, smsService, store, scheduler)
 	healthHandler := handlers.NewHealthHandler(log)
 
+	var trustedProxies []*net.IPNet
+	for _, proxy := range cfg.Security.TrustedProxies {
+		ipNet, err := utils.ParseIPNet(proxy)
+		if err != nil {
+			log.Warn("Ignoring invalid trusted proxy", "proxy", proxy, "error", err)
+			continue
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+
 	return &Server{
 		config:  cfg,
 		logger:  log,
 		handler: webhookHandler,
 		health:  healthHandler,
+		routes:  make(map[string]http.Handler),
+		admin:   make(map[string]http.Handler),
+		limiters: map[string]*rateLimiter{
+			routeWebhook: newRateLimiter(cfg.RateLimit),
+			routeHealth:  newRateLimiter(cfg.HealthRateLimit),
+			routeAdmin:   newRateLimiter(cfg.AdminRateLimit),
+		},
+		trustedProxies: trustedProxies,
+		cors: newCORSPolicy(
+			cfg.Security.EnableCORS,
+			cfg.Security.AllowedOrigins,
+			cfg.Security.CORSAllowCredentials,
+			cfg.Security.CORSMaxAge,
+		),
 	}
 }
 
+// Handle registers an additional POST route; it must be called before Start
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.routes[path] = handler
+}
+
+// HandleAdmin registers an administrative route, limited by security.admin_rate_limit; it must be called before Start
+func (s *Server) HandleAdmin(path string, handler http.Handler) {
+	s.admin[path] = handler
+}
+
 // SetupRoutes configures the HTTP routes
 func (s *Server) SetupRoutes() *mux.Router {
 	router := mux.NewRouter()
 
 	// Webhook endpoint
-	router.HandleFunc("/webhook", s.handler.HandleWebhook).Methods("POST")
+	router.HandleFunc("/webhook", s.handler.HandleWebhook).Methods("POST").Name(routeWebhook)
 
 	// Health check endpoint
-	router.HandleFunc("/health", s.health.HealthCheck).Methods("GET")
+	router.HandleFunc("/health", s.health.HealthCheck).Methods("GET", "OPTIONS").Name(routeHealth)
 
-	// Add CORS middleware
-	router.Use(s.corsMiddleware)
+	// Administrative API routes: the path itself and its subroutes, but not e.g. path + "X"
+	for path, handler := range s.admin {
+		router.Handle(path, handler).Name(routeAdmin + " " + path)
+		router.PathPrefix(path + "/").Handler(handler).Name(routeAdmin + " " + path + "/")
+	}
 
-	return router
-}
+	// Routes registered by other components (e.g. the Telegram webhook)
+	for path, handler := range s.routes {
+		router.Handle(path, handler).Methods("POST")
+	}
 
-// corsMiddleware adds CORS headers
-func (s *Server) corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	// Reject clients over their per-IP rate limit before doing any work
+	router.Use(s.rateLimitMiddleware)
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	// Add CORS headers for browsers on the health and admin routes
+	router.Use(s.corsMiddleware)
 
-		next.ServeHTTP(w, r)
-	})
+	return router
 }
 
-// Start starts the HTTP server
+// Start starts the HTTP server and blocks until it stops; it returns nil after Shutdown
 func (s *Server) Start() error {
 	router := s.SetupRoutes()
 
@@ -76,37 +123,30 @@ func (s *Server) Start() error {
 		WriteTimeout: time.Duration(s.config.Server.WriteTimeout) * time.Second,
 	}
 
+	s.mu.Lock()
+	s.httpServer = server
+	s.mu.Unlock()
+
 	s.logger.Info("Starting webhook server", "address", s.config.GetServerAddress())
 	s.logger.Info("Webhook endpoint available at: http://" + s.config.GetServerAddress() + "/webhook")
 	s.logger.Info("Health check available at: http://" + s.config.GetServerAddress() + "/health")
 
-	return server.ListenAndServe()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
 }
 
-// StartWithGracefulShutdown starts the server with graceful shutdown
-func (s *Server) StartWithGracefulShutdown() error {
-	router := s.SetupRoutes()
+// Shutdown stops accepting connections and waits for in-flight requests until ctx ends
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	server := s.httpServer
+	s.mu.Unlock()
 
-	server := &http.Server{
-		Addr:         s.config.GetServerAddress(),
-		Handler:      router,
-		ReadTimeout:  time.Duration(s.config.Server.ReadTimeout) * time.Second,
-		WriteTimeout: time.Duration(s.config.Server.WriteTimeout) * time.Second,
+	if server == nil {
+		return nil
 	}
 
-	s.logger.Info("Starting webhook server", "address", s.config.GetServerAddress())
-	s.logger.Info("Webhook endpoint available at: http://" + s.config.GetServerAddress() + "/webhook")
-	s.logger.Info("Health check available at: http://" + s.config.GetServerAddress() + "/health")
-
-	// Start server in a goroutine
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	// This would require signal handling in a real application
-	// For now, we'll just return the server
-	return nil
+	s.logger.Info("🛑 Stopping webhook server - finishing in-flight requests")
+	return server.Shutdown(ctx)
 }
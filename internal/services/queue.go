@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SMSJob represents a lead SMS waiting to be sent
+type SMSJob struct {
+	Phone     string    `json:"phone"`
+	UserID    string    `json:"user_id"`
+	LeadID    string    `json:"lead_id"`
+	Platform  string    `json:"platform,omitempty"` // Of the lead, to pick its follow-up sequence once sent
+	QueuedAt  time.Time `json:"queued_at"`
+	Reason    string    `json:"reason"`
+	NotBefore time.Time `json:"not_before"`         // Deferred until then (quiet hours, retries); zero sends as soon as possible
+	Attempts  int       `json:"attempts,omitempty"` // Failed sends so far
+}
+
+// Due reports whether the job may be sent at now
+func (j SMSJob) Due(now time.Time) bool {
+	return !now.Before(j.NotBefore)
+}
+
+// SMSQueue is a thread-safe FIFO of pending SMS jobs
+type SMSQueue struct {
+	mu   sync.Mutex
+	jobs []SMSJob
+}
+
+// NewSMSQueue creates an empty SMS queue
+func NewSMSQueue() *SMSQueue {
+	return &SMSQueue{}
+}
+
+// Push appends a job to the queue
+func (q *SMSQueue) Push(job SMSJob) {
+	if job.QueuedAt.IsZero() {
+		job.QueuedAt = time.Now()
+	}
+
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	q.mu.Unlock()
+}
+
+// PopDue removes and returns the jobs that may be sent at now, keeping the deferred ones in order
+func (q *SMSQueue) PopDue(now time.Time) []SMSJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due, deferred []SMSJob
+	for _, job := range q.jobs {
+		if job.Due(now) {
+			due = append(due, job)
+		} else {
+			deferred = append(deferred, job)
+		}
+	}
+	q.jobs = deferred
+	return due
+}
+
+// Len returns the number of queued jobs
+func (q *SMSQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.jobs)
+}
+
+// SaveFile writes the queued jobs to path so they survive a restart; an empty queue removes the file
+func (q *SMSQueue) SaveFile(path string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove SMS queue file: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(q.jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SMS queue: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create SMS queue directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SMS queue: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace SMS queue file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFile queues the jobs saved in path and removes the file so they are never restored twice
+func (q *SMSQueue) LoadFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read SMS queue: %w", err)
+	}
+
+	var jobs []SMSJob
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return 0, fmt.Errorf("failed to parse SMS queue: %w", err)
+	}
+
+	q.mu.Lock()
+	q.jobs = append(q.jobs, jobs...)
+	q.mu.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		return len(jobs), fmt.Errorf("failed to remove restored SMS queue file: %w", err)
+	}
+
+	return len(jobs), nil
+}
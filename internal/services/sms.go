@@ -1,9 +1,16 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"sync"
+	"time"
 
 	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/optout"
+	"novinhub-webhook/internal/stats"
 	"novinhub-webhook/internal/utils"
 	"novinhub-webhook/pkg/logger"
 )
@@ -12,16 +19,26 @@ import (
 type SMSService struct {
 	logger        *logger.Logger
 	config        *config.Config
+	stats         *stats.Store
+	optOuts       *optout.List
 	ippanelClient *IPPanelClient
+
+	pauseMu   sync.Mutex
+	pause     PauseState
+	queue     *SMSQueue
+	queueWake chan struct{}
+	queueSent func(SMSJob) // Called after a queued job is sent; see OnQueuedSent
+
+	phoneLocks phoneLocks
 }
 
-// NewSMSService creates a new SMS service instance
-func NewSMSService(logger *logger.Logger, cfg *config.Config) *SMSService {
+// NewSMSService creates a new SMS service instance; phones on optOuts are never messaged
+func NewSMSService(logger *logger.Logger, cfg *config.Config, store *stats.Store, optOuts *optout.List) *SMSService {
 	var ippanelClient *IPPanelClient
 
 	// Initialize IPPanel client if API key is provided
 	if cfg.SMS.IPPanel.APIKey != "" {
-		ippanelClient = NewIPPanelClient(cfg.SMS.IPPanel.APIKey)
+		ippanelClient = NewIPPanelClient(cfg.SMS.IPPanel.APIKey, cfg.SMS.Retry, logger)
 		logger.Info("📡 IPPanel SMS Client initialized",
 			"provider", cfg.SMS.Provider,
 			"enabled", cfg.SMS.Enabled,
@@ -30,15 +47,35 @@ func NewSMSService(logger *logger.Logger, cfg *config.Config) *SMSService {
 		logger.Warn("⚠️ IPPanel API key not configured - SMS will be disabled")
 	}
 
-	return &SMSService{
+	s := &SMSService{
 		logger:        logger,
 		config:        cfg,
+		stats:         store,
+		optOuts:       optOuts,
 		ippanelClient: ippanelClient,
+		queue:         NewSMSQueue(),
+		queueWake:     make(chan struct{}, 1),
 	}
+
+	// A pause from the kill switch outlives restarts
+	s.loadPause()
+
+	return s
+}
+
+// OptOuts returns the do-not-contact list checked before every send
+func (s *SMSService) OptOuts() *optout.List {
+	return s.optOuts
 }
 
 // SendSMSWithPattern sends an SMS with the current daily pattern to a phone number
 func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error {
+	return s.SendSMSWithPatternContext(context.Background(), phoneNumber, userID)
+}
+
+// SendSMSWithPatternContext sends an SMS with the current daily pattern, giving up when ctx ends
+// A send cut short by a deadline returns an error for which IsTimeout is true
+func (s *SMSService) SendSMSWithPatternContext(ctx context.Context, phoneNumber string, userID string) error {
 	// Get current pattern from config
 	currentPattern, patternIndex, groupName := s.config.GetCurrentPatternInfo()
 
@@ -56,7 +93,7 @@ func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error
 		"pattern_group", groupName,
 		"pattern_index", patternIndex,
 		"user_id", userID,
-		"enabled", s.config.SMS.Enabled,
+		"enabled", s.config.SMSEnabled(),
 		"pattern_variables", map[string]string{
 			"code": logCode,
 		})
@@ -67,7 +104,7 @@ func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error
 	}
 
 	// Check if SMS is enabled
-	if !s.config.SMS.Enabled {
+	if !s.config.SMSEnabled() {
 		s.logger.Warn("📵 SMS DISABLED - SKIPPING SEND",
 			"phone", phoneNumber,
 			"pattern", currentPattern,
@@ -75,6 +112,56 @@ func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error
 		return nil
 	}
 
+	// Hold the phone until the send is recorded so a concurrent lead can't slip past the limits
+	unlock := s.phoneLocks.lock(utils.NormalizeIranianPhone(phoneNumber))
+	defer unlock()
+
+	// Never message a phone that opted out
+	if err := s.checkOptOut(phoneNumber, userID, currentPattern); err != nil {
+		return err
+	}
+
+	// Check the per-phone limits before queueing or sending
+	if err := s.checkLimits(phoneNumber, userID, currentPattern, time.Now()); err != nil {
+		s.logger.Warn("🚧 SMS LIMIT REACHED - NOT SENDING",
+			"phone", phoneNumber,
+			"user_id", userID,
+			"pattern", currentPattern,
+			"reason", err)
+		s.stats.Record(stats.Event{
+			Kind:    stats.KindSMSLimited,
+			Phone:   utils.NormalizeIranianPhone(phoneNumber),
+			UserID:  userID,
+			Pattern: currentPattern,
+			Error:   err.Error(),
+		})
+		return err
+	}
+
+	// Check the kill switch - callers queue the lead instead
+	if pause := s.PauseState(); pause.Paused {
+		s.logger.Warn("⏸️ SMS PAUSED - NOT SENDING",
+			"phone", phoneNumber,
+			"paused_by", pause.By)
+		return ErrSMSPaused
+	}
+
+	// Hold leads arriving in quiet hours - callers queue them until the returned time
+	if until, quiet := s.config.QuietHours().QuietUntil(time.Now()); quiet {
+		s.logger.Info("🌙 QUIET HOURS - DEFERRING SMS",
+			"phone", phoneNumber,
+			"user_id", userID,
+			"until", until)
+		return &QuietHoursError{Until: until}
+	}
+
+	return s.deliver(ctx, phoneNumber, userID, currentPattern,
+		"pattern_group", groupName,
+		"pattern_index", patternIndex)
+}
+
+// deliver sends pattern to a phone whose checks have passed and records the outcome; logFields are added to the success log
+func (s *SMSService) deliver(ctx context.Context, phoneNumber string, userID string, pattern string, logFields ...interface{}) error {
 	// Check if IPPanel client is configured
 	if s.ippanelClient == nil {
 		s.logger.Error("❌ SMS CLIENT NOT CONFIGURED",
@@ -93,13 +180,139 @@ func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error
 	}
 
 	// Check if pattern is available
-	if currentPattern == "" {
+	if pattern == "" {
 		s.logger.Error("❌ NO PATTERN AVAILABLE",
 			"phone", phoneNumber,
 			"error", "no pattern configured")
 		return fmt.Errorf("no pattern configured for SMS sending")
 	}
 
+	variables := PatternVariables(userID)
+	code := variables["code"]
+
+	// Send SMS using IPPanel
+	messageID, err := s.ippanelClient.SendPatternContext(
+		ctx,
+		pattern,
+		s.config.SMS.IPPanel.Originator,
+		phoneNumber,
+		variables,
+	)
+
+	if err != nil {
+		kind := stats.KindSMSFailed
+		if IsTimeout(err) {
+			kind = stats.KindSMSTimeout
+			s.logger.Error("⏱️ SMS SEND TIMED OUT",
+				"error", err,
+				"phone", phoneNumber,
+				"pattern", pattern)
+		} else {
+			s.logger.Error("❌ SMS SEND FAILED",
+				"error", err,
+				"phone", phoneNumber,
+				"pattern", pattern)
+		}
+		s.stats.Record(stats.Event{
+			Kind:    kind,
+			Phone:   utils.NormalizeIranianPhone(phoneNumber),
+			UserID:  userID,
+			Pattern: pattern,
+			Error:   err.Error(),
+		})
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+
+	s.stats.Record(stats.Event{
+		Kind:      stats.KindSMSSent,
+		Phone:     utils.NormalizeIranianPhone(phoneNumber),
+		UserID:    userID,
+		Pattern:   pattern,
+		MessageID: messageID,
+	})
+
+	s.logger.Info(append([]interface{}{"✅ SMS SENT SUCCESSFULLY",
+		"phone", phoneNumber,
+		"user_id", userID,
+		"message_id", messageID,
+		"pattern", pattern,
+		"originator", s.config.SMS.IPPanel.Originator,
+		"pattern_variables", map[string]string{
+			"code": code,
+		}}, logFields...)...)
+
+	return nil
+}
+
+// checkOptOut returns ErrOptedOut, and records the blocked send, if the phone is on the opt-out list
+func (s *SMSService) checkOptOut(phoneNumber string, userID string, pattern string) error {
+	if !s.optOuts.Contains(phoneNumber) {
+		return nil
+	}
+
+	s.logger.Warn("🚫 PHONE OPTED OUT - NOT SENDING",
+		"phone", phoneNumber,
+		"user_id", userID,
+		"pattern", pattern)
+	s.stats.Record(stats.Event{
+		Kind:    stats.KindSMSOptedOut,
+		Phone:   utils.NormalizeIranianPhone(phoneNumber),
+		UserID:  userID,
+		Pattern: pattern,
+	})
+	return ErrOptedOut
+}
+
+// SendFollowUpContext sends a follow-up SMS of a drip sequence; an empty pattern sends the active daily pattern
+// Follow-ups are planned sends, so sms.limits don't apply, but the opt-out list, the kill switch and quiet hours do
+func (s *SMSService) SendFollowUpContext(ctx context.Context, phoneNumber string, userID string, pattern string) error {
+	if pattern == "" {
+		pattern, _, _ = s.config.GetCurrentPatternInfo()
+	}
+
+	s.logger.Info("📬 FOLLOW-UP SMS INITIATED",
+		"phone", phoneNumber,
+		"user_id", userID,
+		"pattern", pattern)
+
+	if !utils.IsValidIranianPhone(phoneNumber) {
+		return fmt.Errorf("invalid Iranian phone number: %s", phoneNumber)
+	}
+
+	if !s.config.SMSEnabled() {
+		return ErrSMSDisabled
+	}
+
+	unlock := s.phoneLocks.lock(utils.NormalizeIranianPhone(phoneNumber))
+	defer unlock()
+
+	if err := s.checkOptOut(phoneNumber, userID, pattern); err != nil {
+		return err
+	}
+
+	if s.IsPaused() {
+		return ErrSMSPaused
+	}
+
+	if until, quiet := s.config.QuietHours().QuietUntil(time.Now()); quiet {
+		return &QuietHoursError{Until: until}
+	}
+
+	return s.deliver(ctx, phoneNumber, userID, pattern, "follow_up", true)
+}
+
+// IsTimeout reports whether err came from a processing deadline or an HTTP timeout rather than a provider error
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// PatternVariables returns the pattern variables sent with a lead SMS
+func PatternVariables(userID string) map[string]string {
 	// Prepare pattern variables (customize as needed)
 	// Only one variable: 'code' - if userID is empty, use "کاربر گرامی", otherwise use userID
 	var code string
@@ -109,39 +322,118 @@ func (s *SMSService) SendSMSWithPattern(phoneNumber string, userID string) error
 		code = "کاربر گرامی"
 	}
 
-	variables := map[string]string{
+	return map[string]string{
 		"code": code,
 	}
+}
+
+// SendTestSMS sends a pattern to a phone number on behalf of an admin
+// Test sends skip the dedup cache, the kill switch and quiet hours and are recorded as tests
+func (s *SMSService) SendTestSMS(pattern string, phoneNumber string, requestedBy string) (int64, error) {
+	return s.SendTestSMSContext(context.Background(), pattern, phoneNumber, requestedBy)
+}
+
+// SendTestSMSContext sends a test pattern, giving up when ctx ends
+func (s *SMSService) SendTestSMSContext(ctx context.Context, pattern string, phoneNumber string, requestedBy string) (int64, error) {
+	s.logger.Info("🧪 TEST SMS INITIATED",
+		"phone", phoneNumber,
+		"pattern", pattern,
+		"requested_by", requestedBy)
+
+	if !utils.IsValidIranianPhone(phoneNumber) {
+		return 0, fmt.Errorf("invalid Iranian phone number: %s", phoneNumber)
+	}
+
+	if s.optOuts.Contains(phoneNumber) {
+		return 0, ErrOptedOut
+	}
+
+	if s.ippanelClient == nil {
+		return 0, fmt.Errorf("SMS client not configured")
+	}
+
+	if s.config.SMS.IPPanel.Originator == "" {
+		return 0, fmt.Errorf("SMS configuration incomplete: originator not configured")
+	}
+
+	if pattern == "" {
+		return 0, fmt.Errorf("no pattern selected for test SMS")
+	}
 
-	// Send SMS using IPPanel with current pattern
-	messageID, err := s.ippanelClient.SendPattern(
-		currentPattern, // Use current pattern from pattern manager
+	messageID, err := s.ippanelClient.SendPatternContext(
+		ctx,
+		pattern,
 		s.config.SMS.IPPanel.Originator,
 		phoneNumber,
-		variables,
+		PatternVariables(""),
 	)
 
+	event := stats.Event{
+		Phone:     utils.NormalizeIranianPhone(phoneNumber),
+		UserID:    requestedBy,
+		Pattern:   pattern,
+		MessageID: messageID,
+		Test:      true,
+	}
+
 	if err != nil {
-		s.logger.Error("❌ SMS SEND FAILED",
+		s.logger.Error("❌ TEST SMS FAILED",
 			"error", err,
 			"phone", phoneNumber,
-			"pattern", currentPattern)
-		return fmt.Errorf("failed to send SMS: %v", err)
+			"pattern", pattern)
+		event.Kind = stats.KindSMSFailed
+		event.Error = err.Error()
+		s.stats.Record(event)
+		return 0, fmt.Errorf("failed to send test SMS: %v", err)
 	}
 
-	s.logger.Info("✅ SMS SENT SUCCESSFULLY",
+	event.Kind = stats.KindSMSSent
+	s.stats.Record(event)
+
+	s.logger.Info("✅ TEST SMS SENT",
 		"phone", phoneNumber,
-		"user_id", userID,
+		"pattern", pattern,
 		"message_id", messageID,
-		"pattern", currentPattern,
-		"pattern_group", groupName,
-		"pattern_index", patternIndex,
-		"originator", s.config.SMS.IPPanel.Originator,
-		"pattern_variables", map[string]string{
-			"code": code,
-		})
+		"requested_by", requestedBy)
 
-	return nil
+	return messageID, nil
+}
+
+// GetCredit returns the remaining account credit from the SMS provider
+func (s *SMSService) GetCredit() (float64, error) {
+	return s.GetCreditContext(context.Background())
+}
+
+// GetCreditContext returns the remaining account credit, giving up when ctx ends
+func (s *SMSService) GetCreditContext(ctx context.Context) (float64, error) {
+	if s.ippanelClient == nil {
+		return 0, fmt.Errorf("SMS client not configured")
+	}
+
+	return s.ippanelClient.GetCreditContext(ctx)
+}
+
+// GetDeliveryStatus returns the provider delivery status of a sent message
+func (s *SMSService) GetDeliveryStatus(messageID int64) (string, error) {
+	return s.GetDeliveryStatusContext(context.Background(), messageID)
+}
+
+// GetDeliveryStatusContext returns the delivery status of a sent message, giving up when ctx ends
+func (s *SMSService) GetDeliveryStatusContext(ctx context.Context, messageID int64) (string, error) {
+	if s.ippanelClient == nil {
+		return "", fmt.Errorf("SMS client not configured")
+	}
+
+	recipients, _, err := s.ippanelClient.FetchStatusesContext(ctx, messageID, ListParams{Page: 1, Limit: 10})
+	if err != nil {
+		return "", err
+	}
+
+	if len(recipients) == 0 {
+		return "", fmt.Errorf("no delivery status reported for message %d", messageID)
+	}
+
+	return recipients[0].Status, nil
 }
 
 // SendBulkSMS sends SMS to multiple phone numbers
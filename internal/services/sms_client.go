@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,11 @@ import (
 	"net/url"
 	"path"
 	"runtime"
+	"strconv"
 	"time"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/pkg/logger"
 )
 
 const (
@@ -88,6 +93,11 @@ type IPPanelClient struct {
 	Apikey  string
 	Client  *http.Client
 	BaseURL *url.URL
+
+	MaxAttempts int           // Tries per pattern send, from sms.retry.max_attempts
+	RetryDelay  time.Duration // Wait after the first failed try, growing with each one, from sms.retry.delay_seconds
+
+	logger *logger.Logger
 }
 
 // sendPatternReqType send sms with pattern request template
@@ -108,6 +118,17 @@ type getCreditResType struct {
 	Credit float64 `json:"credit"`
 }
 
+// MessageRecipient message recipient delivery status
+type MessageRecipient struct {
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+}
+
+// messageRecipientsResType message recipients response type
+type messageRecipientsResType struct {
+	Deliveries []MessageRecipient `json:"deliveries"`
+}
+
 // fieldErrsRes field errors response type
 type fieldErrsRes struct {
 	Errors FieldErrs `json:"error"`
@@ -118,22 +139,32 @@ type defaultErrsRes struct {
 	Errors string `json:"error"`
 }
 
-// NewIPPanelClient create new ippanel sms instance
-func NewIPPanelClient(apikey string) *IPPanelClient {
+// NewIPPanelClient create new ippanel sms instance retrying pattern sends as configured in retry
+func NewIPPanelClient(apikey string, retry config.RetryConfig, logger *logger.Logger) *IPPanelClient {
 	u, _ := url.Parse(Endpoint)
 	client := &http.Client{
 		Transport: http.DefaultTransport,
 		Timeout:   httpClientTimeout,
 	}
+
+	// Fewer than one attempt means no retries, not no send
+	maxAttempts := retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
 	return &IPPanelClient{
-		Apikey:  apikey,
-		Client:  client,
-		BaseURL: u,
+		Apikey:      apikey,
+		Client:      client,
+		BaseURL:     u,
+		MaxAttempts: maxAttempts,
+		RetryDelay:  time.Duration(retry.DelaySeconds) * time.Second,
+		logger:      logger,
 	}
 }
 
-// request preform http request
-func (sms IPPanelClient) request(method string, uri string, params map[string]string, data interface{}) (*BaseResponse, error) {
+// request preform http request; ctx bounds the whole request
+func (sms IPPanelClient) request(ctx context.Context, method string, uri string, params map[string]string, data interface{}) (*BaseResponse, error) {
 	u := *sms.BaseURL
 	// join base url with extra path
 	u.Path = path.Join(sms.BaseURL.Path, uri)
@@ -151,7 +182,7 @@ func (sms IPPanelClient) request(method string, uri string, params map[string]st
 	}
 
 	requestBody := bytes.NewBuffer(marshaledBody)
-	req, err := http.NewRequest(method, u.String(), requestBody)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), requestBody)
 	if err != nil {
 		return nil, err
 	}
@@ -196,13 +227,13 @@ func (sms IPPanelClient) request(method string, uri string, params map[string]st
 }
 
 // get do get request
-func (sms IPPanelClient) get(uri string, params map[string]string) (*BaseResponse, error) {
-	return sms.request("GET", uri, params, nil)
+func (sms IPPanelClient) get(ctx context.Context, uri string, params map[string]string) (*BaseResponse, error) {
+	return sms.request(ctx, "GET", uri, params, nil)
 }
 
 // post do post request
-func (sms IPPanelClient) post(uri string, contentType string, data interface{}) (*BaseResponse, error) {
-	return sms.request("POST", uri, nil, data)
+func (sms IPPanelClient) post(ctx context.Context, uri string, contentType string, data interface{}) (*BaseResponse, error) {
+	return sms.request(ctx, "POST", uri, nil, data)
 }
 
 // parseErrors ...
@@ -228,6 +259,11 @@ func (sms IPPanelClient) parseErrors(res *BaseResponse) error {
 
 // SendPattern send a message with pattern
 func (sms *IPPanelClient) SendPattern(patternCode string, originator string, recipient string, values map[string]string) (int64, error) {
+	return sms.SendPatternContext(context.Background(), patternCode, originator, recipient, values)
+}
+
+// SendPatternContext send a message with pattern; the API calls are cancelled when ctx ends
+func (sms *IPPanelClient) SendPatternContext(ctx context.Context, patternCode string, originator string, recipient string, values map[string]string) (int64, error) {
 	data := sendPatternReqType{
 		Code:      patternCode,
 		Sender:    originator,
@@ -235,22 +271,27 @@ func (sms *IPPanelClient) SendPattern(patternCode string, originator string, rec
 		Variable:  values,
 	}
 
-	// Debug logging
-	jsonData, _ := json.Marshal(data)
-	fmt.Printf("🔍 SMS Request: %s\n", string(jsonData))
+	sms.logger.Debug("🔍 SMS REQUEST",
+		"pattern", patternCode,
+		"recipient", recipient,
+		"variables", values)
 
-	// Retry mechanism (3 attempts)
-	maxAttempts := 3
+	maxAttempts := sms.MaxAttempts
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		fmt.Printf("🔄 SMS Attempt %d/%d\n", attempt, maxAttempts)
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("SMS send cancelled before attempt %d: %w", attempt, err)
+		}
+		sms.logger.Debug("🔄 SMS ATTEMPT", "attempt", attempt, "max_attempts", maxAttempts)
 
-		_res, err := sms.post("/sms/pattern/normal/send", "application/json", data)
+		_res, err := sms.post(ctx, "/sms/pattern/normal/send", "application/json", data)
 		if err != nil {
-			if attempt == maxAttempts {
-				return 0, fmt.Errorf("SMS API request failed after %d attempts: %v", maxAttempts, err)
+			if attempt == maxAttempts || ctx.Err() != nil {
+				return 0, fmt.Errorf("SMS API request failed after %d attempts: %w", attempt, err)
+			}
+			sms.logger.Warn("⚠️ SMS attempt failed - retrying", "attempt", attempt, "error", err)
+			if err := sms.retryBackoff(ctx, attempt); err != nil {
+				return 0, err
 			}
-			fmt.Printf("⚠️ SMS Attempt %d failed, retrying... Error: %v\n", attempt, err)
-			time.Sleep(time.Duration(attempt) * time.Second) // Exponential backoff
 			continue
 		}
 
@@ -258,21 +299,26 @@ func (sms *IPPanelClient) SendPattern(patternCode string, originator string, rec
 			if attempt == maxAttempts {
 				return 0, fmt.Errorf("SMS API returned empty response after %d attempts", maxAttempts)
 			}
-			fmt.Printf("⚠️ SMS Attempt %d returned empty response, retrying...\n", attempt)
-			time.Sleep(time.Duration(attempt) * time.Second)
+			sms.logger.Warn("⚠️ SMS attempt returned an empty response - retrying", "attempt", attempt)
+			if err := sms.retryBackoff(ctx, attempt); err != nil {
+				return 0, err
+			}
 			continue
 		}
 
-		// Debug logging
-		fmt.Printf("🔍 SMS Response Status: %s, Code: %d\n", _res.Status, _res.Code)
-		fmt.Printf("🔍 SMS Response Data: %s\n", string(_res.Data))
+		sms.logger.Debug("🔍 SMS RESPONSE",
+			"status", _res.Status,
+			"code", _res.Code,
+			"data", string(_res.Data))
 
 		if _res.Data == nil {
 			if attempt == maxAttempts {
 				return 0, fmt.Errorf("SMS API returned null data after %d attempts", maxAttempts)
 			}
-			fmt.Printf("⚠️ SMS Attempt %d returned null data, retrying...\n", attempt)
-			time.Sleep(time.Duration(attempt) * time.Second)
+			sms.logger.Warn("⚠️ SMS attempt returned null data - retrying", "attempt", attempt)
+			if err := sms.retryBackoff(ctx, attempt); err != nil {
+				return 0, err
+			}
 			continue
 		}
 
@@ -281,8 +327,10 @@ func (sms *IPPanelClient) SendPattern(patternCode string, originator string, rec
 			if attempt == maxAttempts {
 				return 0, fmt.Errorf("failed to parse SMS API response after %d attempts: %v. Raw data: %s", maxAttempts, err, string(_res.Data))
 			}
-			fmt.Printf("⚠️ SMS Attempt %d failed to parse response, retrying... Error: %v\n", attempt, err)
-			time.Sleep(time.Duration(attempt) * time.Second)
+			sms.logger.Warn("⚠️ SMS attempt response could not be parsed - retrying", "attempt", attempt, "error", err)
+			if err := sms.retryBackoff(ctx, attempt); err != nil {
+				return 0, err
+			}
 			continue
 		}
 
@@ -290,12 +338,14 @@ func (sms *IPPanelClient) SendPattern(patternCode string, originator string, rec
 			if attempt == maxAttempts {
 				return 0, fmt.Errorf("SMS API returned invalid message ID after %d attempts. Raw response: %s", maxAttempts, string(_res.Data))
 			}
-			fmt.Printf("⚠️ SMS Attempt %d returned invalid message ID, retrying...\n", attempt)
-			time.Sleep(time.Duration(attempt) * time.Second)
+			sms.logger.Warn("⚠️ SMS attempt returned an invalid message ID - retrying", "attempt", attempt)
+			if err := sms.retryBackoff(ctx, attempt); err != nil {
+				return 0, err
+			}
 			continue
 		}
 
-		fmt.Printf("✅ SMS sent successfully on attempt %d with MessageId: %d\n", attempt, res.MessageId)
+		sms.logger.Debug("✅ SMS ACCEPTED", "attempt", attempt, "message_id", res.MessageId)
 		return res.MessageId, nil
 	}
 
@@ -304,7 +354,12 @@ func (sms *IPPanelClient) SendPattern(patternCode string, originator string, rec
 
 // GetCredit get credit for user
 func (sms *IPPanelClient) GetCredit() (float64, error) {
-	_res, err := sms.get("/sms/accounting/credit/show", nil)
+	return sms.GetCreditContext(context.Background())
+}
+
+// GetCreditContext get credit for user; the request is cancelled when ctx ends
+func (sms *IPPanelClient) GetCreditContext(ctx context.Context) (float64, error) {
+	_res, err := sms.get(ctx, "/sms/accounting/credit/show", nil)
 	if err != nil {
 		return 0, err
 	}
@@ -316,3 +371,39 @@ func (sms *IPPanelClient) GetCredit() (float64, error) {
 
 	return res.Credit, nil
 }
+
+// FetchStatuses get message recipients delivery statuses
+func (sms *IPPanelClient) FetchStatuses(messageID int64, pp ListParams) ([]MessageRecipient, *PaginationInfo, error) {
+	return sms.FetchStatusesContext(context.Background(), messageID, pp)
+}
+
+// FetchStatusesContext get message recipients delivery statuses; the request is cancelled when ctx ends
+func (sms *IPPanelClient) FetchStatusesContext(ctx context.Context, messageID int64, pp ListParams) ([]MessageRecipient, *PaginationInfo, error) {
+	_res, err := sms.get(ctx, fmt.Sprintf("/sms/message/show-recipient/message-id/%d", messageID), map[string]string{
+		"page":  strconv.FormatInt(pp.Page, 10),
+		"limit": strconv.FormatInt(pp.Limit, 10),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &messageRecipientsResType{}
+	if err = json.Unmarshal(_res.Data, res); err != nil {
+		return nil, nil, err
+	}
+
+	return res.Deliveries, _res.Meta, nil
+}
+
+// retryBackoff waits RetryDelay times the number of failed attempts, returning early if ctx ends
+func (sms *IPPanelClient) retryBackoff(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(time.Duration(attempt) * sms.RetryDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("SMS send cancelled while waiting to retry: %w", ctx.Err())
+	}
+}
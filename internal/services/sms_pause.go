@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/internal/utils"
+)
+
+const (
+	// queueCheckInterval is how often the queue worker looks for jobs it can send
+	queueCheckInterval = 10 * time.Second
+	// queueMaxAttempts is how many failed sends drop a queued job
+	queueMaxAttempts = 5
+	// queueRetryDelay is the wait after the first failed send, growing with each attempt
+	queueRetryDelay = 5 * time.Minute
+)
+
+// ErrSMSPaused is returned when sending is paused by the kill switch
+var ErrSMSPaused = errors.New("SMS sending is paused")
+
+// PauseState describes a manual pause of SMS sending
+type PauseState struct {
+	Paused bool      `json:"paused"`
+	By     string    `json:"by"`
+	At     time.Time `json:"at"`
+	Until  time.Time `json:"until,omitempty"` // zero means paused until resumed manually
+}
+
+// Pause stops SMS sending immediately; a zero duration pauses until Resume is called
+func (s *SMSService) Pause(by string, duration time.Duration) PauseState {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	s.pause = PauseState{
+		Paused: true,
+		By:     by,
+		At:     time.Now(),
+	}
+	if duration > 0 {
+		s.pause.Until = s.pause.At.Add(duration)
+	}
+	s.savePauseLocked()
+
+	s.logger.Warn("⏸️ SMS SENDING PAUSED",
+		"by", by,
+		"until", s.pause.Until)
+
+	return s.pause
+}
+
+// Resume re-enables SMS sending and wakes the queue worker
+func (s *SMSService) Resume(by string) {
+	s.pauseMu.Lock()
+	s.pause = PauseState{}
+	s.savePauseLocked()
+	s.pauseMu.Unlock()
+
+	s.logger.Info("▶️ SMS SENDING RESUMED", "by", by, "queued_jobs", s.queue.Len())
+	s.wakeQueue()
+}
+
+// PauseState returns the current kill switch state, auto-resuming expired pauses
+func (s *SMSService) PauseState() PauseState {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if s.pause.Paused && !s.pause.Until.IsZero() && time.Now().After(s.pause.Until) {
+		s.logger.Info("▶️ SMS SENDING AUTO-RESUMED",
+			"paused_by", s.pause.By,
+			"paused_at", s.pause.At)
+		s.pause = PauseState{}
+		s.savePauseLocked()
+	}
+
+	return s.pause
+}
+
+// loadPause restores the pause saved in sms.pause_file; an expired one is dropped by the next PauseState
+func (s *SMSService) loadPause() {
+	path := s.config.SMS.PauseFile
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &s.pause)
+	}
+	if err != nil {
+		// Fail safe: a kill switch that can't be read stays on until an owner resumes
+		s.pause = PauseState{Paused: true, By: "unreadable pause file", At: time.Now()}
+		s.logger.Error("Failed to restore SMS pause - keeping sending paused", "file_path", path, "error", err)
+		return
+	}
+
+	if s.pause.Paused {
+		s.logger.Warn("⏸️ SMS SENDING STILL PAUSED",
+			"by", s.pause.By,
+			"at", s.pause.At,
+			"until", s.pause.Until)
+	}
+}
+
+// savePauseLocked saves a pause to sms.pause_file, or removes the file once sending resumes; callers hold pauseMu
+func (s *SMSService) savePauseLocked() {
+	path := s.config.SMS.PauseFile
+	if path == "" {
+		return
+	}
+
+	if err := writePause(path, s.pause); err != nil {
+		s.logger.Error("Failed to save SMS pause - it will not survive a restart", "file_path", path, "error", err)
+	}
+}
+
+// writePause writes state atomically, removing the file when sending is not paused
+func writePause(path string, state PauseState) error {
+	if !state.Paused {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SMS pause: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create SMS pause directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SMS pause: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// IsPaused reports whether SMS sending is currently paused
+func (s *SMSService) IsPaused() bool {
+	return s.PauseState().Paused
+}
+
+// Enqueue holds a lead SMS until sending is resumed, or until job.NotBefore for deferred jobs
+func (s *SMSService) Enqueue(job SMSJob) {
+	s.queue.Push(job)
+
+	kind := stats.KindSMSQueued
+	if !job.NotBefore.IsZero() {
+		kind = stats.KindSMSDeferred
+	}
+	s.stats.Record(stats.Event{
+		Kind:   kind,
+		Phone:  utils.NormalizeIranianPhone(job.Phone),
+		UserID: job.UserID,
+		LeadID: job.LeadID,
+	})
+
+	s.logger.Info("📥 SMS QUEUED",
+		"phone", job.Phone,
+		"lead_id", job.LeadID,
+		"reason", job.Reason,
+		"not_before", job.NotBefore,
+		"queued_jobs", s.queue.Len())
+}
+
+// QueueLen returns the number of SMS jobs waiting to be sent
+func (s *SMSService) QueueLen() int {
+	return s.queue.Len()
+}
+
+// OnQueuedSent sets fn to be called after a queued job is sent, e.g. to start the lead's follow-ups
+// Set it before starting RunQueue
+func (s *SMSService) OnQueuedSent(fn func(SMSJob)) {
+	s.queueSent = fn
+}
+
+// wakeQueue asks the queue worker to check for jobs without waiting for the next tick
+func (s *SMSService) wakeQueue() {
+	select {
+	case s.queueWake <- struct{}{}:
+	default:
+	}
+}
+
+// RunQueue sends queued jobs whenever sending is not paused, until ctx is cancelled
+func (s *SMSService) RunQueue(ctx context.Context) {
+	ticker := time.NewTicker(queueCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.queueWake:
+		}
+
+		if s.IsPaused() || s.queue.Len() == 0 {
+			continue
+		}
+
+		s.sendQueued(ctx)
+	}
+}
+
+// sendQueued sends the due queued jobs until ctx ends; jobs not sent stay queued
+// A send cut short by ctx is kept for Shutdown to save; a failed one is retried later, up to queueMaxAttempts
+func (s *SMSService) sendQueued(ctx context.Context) {
+	jobs := s.queue.PopDue(time.Now())
+	for i, job := range jobs {
+		if ctx.Err() != nil {
+			for _, remaining := range jobs[i:] {
+				s.queue.Push(remaining)
+			}
+			return
+		}
+
+		err := s.SendSMSWithPatternContext(ctx, job.Phone, job.UserID)
+		if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			// Shutting down - keep this job and the rest for Shutdown to send or save
+			for _, remaining := range jobs[i:] {
+				s.queue.Push(remaining)
+			}
+			return
+		}
+		if errors.Is(err, ErrSMSPaused) {
+			// Paused again while draining - keep the job for later
+			s.queue.Push(job)
+			continue
+		}
+		var quiet *QuietHoursError
+		if errors.As(err, &quiet) {
+			// Quiet hours started while the job waited - hold it until they end
+			job.NotBefore = quiet.Until
+			s.queue.Push(job)
+			continue
+		}
+		if errors.Is(err, ErrSMSLimited) || errors.Is(err, ErrOptedOut) {
+			// The phone reached its limit or opted out while the job waited - drop it
+			continue
+		}
+		if err != nil {
+			s.retryQueued(job, err)
+			continue
+		}
+
+		s.logger.Info("✅ QUEUED SMS SENT",
+			"phone", job.Phone,
+			"lead_id", job.LeadID,
+			"queued_for", time.Since(job.QueuedAt).String())
+
+		if s.queueSent != nil {
+			s.queueSent(job)
+		}
+	}
+}
+
+// retryQueued queues a job whose send failed again after a growing delay, dropping it after queueMaxAttempts
+func (s *SMSService) retryQueued(job SMSJob, err error) {
+	job.Attempts++
+	if job.Attempts >= queueMaxAttempts {
+		s.logger.Error("❌ QUEUED SMS DROPPED AFTER REPEATED FAILURES",
+			"error", err,
+			"phone", job.Phone,
+			"lead_id", job.LeadID,
+			"attempts", job.Attempts)
+		return
+	}
+
+	job.NotBefore = time.Now().Add(time.Duration(job.Attempts) * queueRetryDelay)
+	s.queue.Push(job)
+
+	s.logger.Error("Failed to send queued SMS - retrying later",
+		"error", err,
+		"phone", job.Phone,
+		"lead_id", job.LeadID,
+		"attempts", job.Attempts,
+		"not_before", job.NotBefore)
+}
+
+// RestoreQueue queues the leads saved by the last Shutdown and removes sms.queue_file
+// Only the process running the queue worker may call it, before starting RunQueue
+func (s *SMSService) RestoreQueue() {
+	if s.config.SMS.QueueFile == "" {
+		return
+	}
+
+	restored, err := s.queue.LoadFile(s.config.SMS.QueueFile)
+	if err != nil {
+		s.logger.Error("Failed to restore SMS queue", "file_path", s.config.SMS.QueueFile, "error", err)
+	}
+	if restored > 0 {
+		s.logger.Info("📥 SMS QUEUE RESTORED", "queued_jobs", restored, "file_path", s.config.SMS.QueueFile)
+	}
+}
+
+// Shutdown sends what it can of the queue before ctx ends and saves the rest to sms.queue_file
+// The queue worker must have stopped first
+func (s *SMSService) Shutdown(ctx context.Context) error {
+	if !s.IsPaused() && s.queue.Len() > 0 {
+		s.logger.Info("📤 Draining SMS queue before shutdown", "queued_jobs", s.queue.Len())
+		s.sendQueued(ctx)
+	}
+
+	remaining := s.queue.Len()
+	if s.config.SMS.QueueFile == "" {
+		if remaining > 0 {
+			s.logger.Warn("⚠️ SMS jobs lost on shutdown - sms.queue_file is not set", "queued_jobs", remaining)
+		}
+		return nil
+	}
+
+	if err := s.queue.SaveFile(s.config.SMS.QueueFile); err != nil {
+		return err
+	}
+	if remaining > 0 {
+		s.logger.Info("💾 SMS QUEUE SAVED", "queued_jobs", remaining, "file_path", s.config.SMS.QueueFile)
+	}
+	return nil
+}
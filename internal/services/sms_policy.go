@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/internal/stats"
+	"novinhub-webhook/internal/utils"
+)
+
+// ErrSMSLimited is returned when a lead SMS would break an sms.limits rule
+var ErrSMSLimited = errors.New("SMS limit reached for this phone")
+
+// ErrOptedOut is returned when the phone is on the opt-out list
+var ErrOptedOut = errors.New("phone has opted out of SMS")
+
+// ErrSMSDisabled is returned for follow-ups while sms.enabled is false
+var ErrSMSDisabled = errors.New("SMS sending is disabled")
+
+// ErrQuietHours is returned when a lead SMS falls in sms.quiet_hours
+var ErrQuietHours = errors.New("SMS quiet hours")
+
+// QuietHoursError reports when the quiet hours a send fell in end
+type QuietHoursError struct {
+	Until time.Time
+}
+
+func (e *QuietHoursError) Error() string {
+	return fmt.Sprintf("SMS quiet hours until %s", e.Until.In(utils.TehranLocation()).Format("2006-01-02 15:04"))
+}
+
+func (e *QuietHoursError) Unwrap() error {
+	return ErrQuietHours
+}
+
+// phoneLockStripes is the number of locks phones are spread over so checks and sends for one phone don't interleave
+const phoneLockStripes = 64
+
+// phoneLocks serializes the policy check and send for each phone
+type phoneLocks [phoneLockStripes]sync.Mutex
+
+// lock locks the stripe of a normalized phone and returns its unlock function
+func (l *phoneLocks) lock(phone string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(phone))
+	mu := &l[h.Sum32()%phoneLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// LimitError describes the sms.limits rule a send would break
+type LimitError struct {
+	Rule  config.SMSLimitRule
+	Count int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %d of %d sends (%s) in the last %d hours", ErrSMSLimited, e.Count, e.Rule.Max, e.Rule.Scope, e.Rule.WindowHours)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrSMSLimited
+}
+
+// checkLimits returns a *LimitError if sending pattern to phone for userID would exceed any sms.limits rule
+// Sends are counted from recorded stats; test sends from the bot don't count
+func (s *SMSService) checkLimits(phone, userID, pattern string, now time.Time) error {
+	rules := s.config.SMSLimits()
+	if len(rules) == 0 || s.stats == nil {
+		return nil
+	}
+
+	sent := s.stats.ByPhone(utils.NormalizeIranianPhone(phone))
+	for _, rule := range rules {
+		since := now.Add(-rule.Window())
+
+		count := 0
+		for _, event := range sent {
+			if event.Kind != stats.KindSMSSent || event.Test || event.Time.Before(since) {
+				continue
+			}
+			switch rule.Scope {
+			case config.LimitScopePhoneAccount:
+				if event.UserID != userID {
+					continue
+				}
+			case config.LimitScopePhonePattern:
+				if event.Pattern != pattern {
+					continue
+				}
+			}
+			count++
+		}
+
+		if count >= rule.Max {
+			return &LimitError{Rule: rule, Count: count}
+		}
+	}
+
+	return nil
+}
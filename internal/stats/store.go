@@ -0,0 +1,322 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"novinhub-webhook/internal/config"
+	"novinhub-webhook/pkg/logger"
+)
+
+// Event kinds recorded by the webhook handler and SMS service
+const (
+	KindWebhook      = "webhook"
+	KindLead         = "lead"
+	KindSMSSent      = "sms_sent"
+	KindSMSDuplicate = "sms_duplicate"
+	KindSMSFailed    = "sms_failed"
+	KindSMSTimeout   = "sms_timeout"
+	KindSMSLimited   = "sms_limited"
+	KindSMSOptedOut  = "sms_opted_out"
+	KindSMSQueued    = "sms_queued"
+	KindSMSDeferred  = "sms_deferred"
+)
+
+// Event represents a single recorded occurrence
+type Event struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`
+	EventType    string    `json:"event_type,omitempty"`
+	Platform     string    `json:"platform,omitempty"`
+	SocialUserID string    `json:"social_user_id,omitempty"`
+	Phone        string    `json:"phone,omitempty"`
+	UserID       string    `json:"user_id,omitempty"`
+	LeadID       string    `json:"lead_id,omitempty"`
+	LeadType     string    `json:"lead_type,omitempty"`
+	Valid        bool      `json:"valid,omitempty"`
+	Pattern      string    `json:"pattern,omitempty"`
+	MessageID    int64     `json:"message_id,omitempty"`
+	Test         bool      `json:"test,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Store keeps recorded events in memory and appends them to a JSON lines file
+type Store struct {
+	logger    *logger.Logger
+	filePath  string
+	retention time.Duration
+
+	mu         sync.RWMutex
+	events     []Event
+	lastPruned time.Time
+	modTime    time.Time // Of the file when last read or written
+}
+
+// NewStore creates a new stats store and loads previously recorded events
+func NewStore(logger *logger.Logger, cfg *config.Config) (*Store, error) {
+	s := &Store{
+		logger:    logger,
+		filePath:  cfg.Stats.FilePath,
+		retention: time.Duration(cfg.Stats.RetentionDays) * 24 * time.Hour,
+	}
+
+	if s.filePath == "" {
+		logger.Warn("⚠️ Stats file path not configured - statistics will be kept in memory only")
+		return s, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create stats directory: %w", err)
+	}
+
+	dropped, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Compact the file so expired events don't accumulate across restarts
+	if dropped > 0 {
+		if err := s.rewrite(); err != nil {
+			return nil, fmt.Errorf("failed to compact stats file: %w", err)
+		}
+	}
+
+	logger.Info("📊 Stats store initialized",
+		"file_path", s.filePath,
+		"events", len(s.events),
+		"retention_days", cfg.Stats.RetentionDays)
+
+	return s, nil
+}
+
+// load replaces the events in memory with those in the stats file, returning how many were past retention
+func (s *Store) load() (int, error) {
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to open stats file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat stats file: %w", err)
+	}
+
+	cutoff := s.cutoff(time.Now())
+	dropped := 0
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			s.logger.Warn("Skipping malformed stats line", "error", err)
+			continue
+		}
+		if event.Time.Before(cutoff) {
+			dropped++
+			continue
+		}
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	s.events = events
+	s.modTime = info.ModTime()
+	return dropped, nil
+}
+
+// refresh rereads the file if another process (e.g. the webhook server, for the standalone bot) wrote to it since
+func (s *Store) refresh() {
+	if s.filePath == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.filePath)
+	if err != nil || !info.ModTime().After(s.modTime) {
+		return
+	}
+
+	if _, err := s.load(); err != nil {
+		s.logger.Error("Failed to reload stats file - keeping events in memory", "error", err)
+	}
+}
+
+// touchLocked records the file's modification time after a write of our own; callers hold mu
+func (s *Store) touchLocked() {
+	if info, err := os.Stat(s.filePath); err == nil {
+		s.modTime = info.ModTime()
+	}
+}
+
+// rewrite replaces the stats file with the events currently held in memory
+func (s *Store) rewrite() error {
+	tmpPath := s.filePath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range s.events {
+		if err := encoder.Encode(event); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		return err
+	}
+	s.touchLocked()
+	return nil
+}
+
+// cutoff returns the oldest time kept by the store
+func (s *Store) cutoff(now time.Time) time.Time {
+	if s.retention <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-s.retention)
+}
+
+// Record stores an event in memory and appends it to the stats file
+func (s *Store) Record(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.pruneLocked(event.Time)
+	s.mu.Unlock()
+
+	if s.filePath == "" {
+		return
+	}
+
+	if err := s.appendToFile(event); err != nil {
+		s.logger.Error("Failed to persist stats event", "error", err, "kind", event.Kind)
+	}
+}
+
+// appendToFile writes a single event as a JSON line
+func (s *Store) appendToFile(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	s.touchLocked()
+	return nil
+}
+
+// pruneLocked drops events past retention at most once per hour
+func (s *Store) pruneLocked(now time.Time) {
+	if s.retention <= 0 || now.Sub(s.lastPruned) < time.Hour {
+		return
+	}
+	s.lastPruned = now
+
+	cutoff := s.cutoff(now)
+	kept := s.events[:0]
+	for _, event := range s.events {
+		if !event.Time.Before(cutoff) {
+			kept = append(kept, event)
+		}
+	}
+	s.events = kept
+}
+
+// Events returns a copy of the events recorded in [from, to)
+func (s *Store) Events(from, to time.Time) []Event {
+	s.refresh()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []Event
+	for _, event := range s.events {
+		if event.Time.Before(from) || !event.Time.Before(to) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// ByPhone returns all events recorded for a normalized phone number, oldest first
+func (s *Store) ByPhone(phone string) []Event {
+	s.refresh()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []Event
+	for _, event := range s.events {
+		if event.Phone == phone {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// PhonesBySocialUser returns the distinct phone numbers of leads sent by a social media user, oldest first
+func (s *Store) PhonesBySocialUser(socialUserID string) []string {
+	s.refresh()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var phones []string
+	for _, event := range s.events {
+		if event.Kind != KindLead || event.SocialUserID != socialUserID || event.Phone == "" || seen[event.Phone] {
+			continue
+		}
+		seen[event.Phone] = true
+		phones = append(phones, event.Phone)
+	}
+
+	return phones
+}
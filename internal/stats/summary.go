@@ -0,0 +1,76 @@
+package stats
+
+import "time"
+
+// Summary aggregates recorded events over a time range
+type Summary struct {
+	From time.Time
+	To   time.Time
+
+	Webhooks       int
+	WebhooksByType map[string]int
+
+	Leads           int
+	LeadsByPlatform map[string]int
+	ValidPhones     int
+	InvalidPhones   int
+
+	SMSSent       int
+	SMSDuplicate  int
+	SMSFailed     int
+	SMSTimedOut   int
+	SMSLimited    int
+	SMSOptedOut   int
+	SentByPattern map[string]int
+	TestSMS       int
+}
+
+// Summarize aggregates the events recorded in [from, to)
+func (s *Store) Summarize(from, to time.Time) Summary {
+	summary := Summary{
+		From:            from,
+		To:              to,
+		WebhooksByType:  make(map[string]int),
+		LeadsByPlatform: make(map[string]int),
+		SentByPattern:   make(map[string]int),
+	}
+
+	for _, event := range s.Events(from, to) {
+		// Test sends from the bot are counted separately so they don't skew lead figures
+		if event.Test {
+			summary.TestSMS++
+			continue
+		}
+
+		switch event.Kind {
+		case KindWebhook:
+			summary.Webhooks++
+			summary.WebhooksByType[event.EventType]++
+		case KindLead:
+			summary.Leads++
+			summary.LeadsByPlatform[event.Platform]++
+			if event.LeadType == "number" {
+				if event.Valid {
+					summary.ValidPhones++
+				} else {
+					summary.InvalidPhones++
+				}
+			}
+		case KindSMSSent:
+			summary.SMSSent++
+			summary.SentByPattern[event.Pattern]++
+		case KindSMSDuplicate:
+			summary.SMSDuplicate++
+		case KindSMSFailed:
+			summary.SMSFailed++
+		case KindSMSTimeout:
+			summary.SMSTimedOut++
+		case KindSMSLimited:
+			summary.SMSLimited++
+		case KindSMSOptedOut:
+			summary.SMSOptedOut++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// ToJalali converts a Gregorian date to the Jalali (Solar Hijri) calendar
+func ToJalali(t time.Time) (year, month, day int) {
+	gy, gm, gd := t.Year(), int(t.Month()), t.Day()
+
+	// Days before each Gregorian month in a non-leap year
+	daysBefore := [12]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+
+	gy2 := gy
+	if gm > 2 {
+		gy2 = gy + 1
+	}
+	days := 355666 + 365*gy + (gy2+3)/4 - (gy2+99)/100 + (gy2+399)/400 + gd + daysBefore[gm-1]
+
+	year = -1595 + 33*(days/12053)
+	days %= 12053
+	year += 4 * (days / 1461)
+	days %= 1461
+	if days > 365 {
+		year += (days - 1) / 365
+		days = (days - 1) % 365
+	}
+
+	if days < 186 {
+		month = 1 + days/31
+		day = 1 + days%31
+	} else {
+		month = 7 + (days-186)/30
+		day = 1 + (days-186)%30
+	}
+	return year, month, day
+}
+
+// FormatJalaliDate formats a time as a Jalali date in Tehran, e.g. 1403/07/26
+func FormatJalaliDate(t time.Time) string {
+	y, m, d := ToJalali(t.In(TehranLocation()))
+	return fmt.Sprintf("%04d/%02d/%02d", y, m, d)
+}
+
+// FormatJalaliDateTime formats a time as a Jalali date and clock time in Tehran, e.g. 1403/07/26 15:04:05
+func FormatJalaliDateTime(t time.Time) string {
+	t = t.In(TehranLocation())
+	return FormatJalaliDate(t) + " " + t.Format("15:04:05")
+}
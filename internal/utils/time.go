@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	tehranOnce     sync.Once
+	tehranLocation *time.Location
+)
+
+// TehranLocation returns the Asia/Tehran time zone
+// Falls back to a fixed +03:30 offset when tzdata is not installed (e.g. alpine images)
+func TehranLocation() *time.Location {
+	tehranOnce.Do(func() {
+		loc, err := time.LoadLocation("Asia/Tehran")
+		if err != nil {
+			loc = time.FixedZone("IRST", 3*60*60+30*60)
+		}
+		tehranLocation = loc
+	})
+	return tehranLocation
+}
+
+// TehranNow returns the current time in Tehran
+func TehranNow() time.Time {
+	return time.Now().In(TehranLocation())
+}
+
+// StartOfDay returns midnight of the given time's day in Tehran
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(TehranLocation())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, TehranLocation())
+}
@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -26,3 +29,53 @@ func NewWithLevel(level logrus.Level) *Logger {
 
 	return &Logger{Logger: logger}
 }
+
+// SetLevelName sets the level from a config value such as "debug" or "info"
+func (l *Logger) SetLevelName(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	l.SetLevel(parsed)
+	return nil
+}
+
+// Redact masks the given values (e.g. API keys and tokens) wherever they appear in log messages or fields
+func (l *Logger) Redact(secrets ...string) {
+	var values []string
+	for _, secret := range secrets {
+		if secret != "" {
+			values = append(values, secret, "[REDACTED]")
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	l.AddHook(&redactHook{replacer: strings.NewReplacer(values...)})
+}
+
+// redactHook rewrites log entries before they are formatted
+type redactHook struct {
+	replacer *strings.Replacer
+}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.replacer.Replace(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.replacer.Replace(v)
+		case error:
+			entry.Data[key] = h.replacer.Replace(v.Error())
+		case fmt.Stringer:
+			entry.Data[key] = h.replacer.Replace(v.String())
+		}
+	}
+	return nil
+}